@@ -2,18 +2,30 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
+	"hackaton-service-api/internal/auth"
 	"hackaton-service-api/internal/entity"
 	"hackaton-service-api/internal/handler"
 	"hackaton-service-api/internal/infra/database"
+	"hackaton-service-api/internal/infra/events"
+	"hackaton-service-api/internal/infra/mail"
+	"hackaton-service-api/internal/infra/queue"
 	"hackaton-service-api/internal/infra/service"
+	"hackaton-service-api/internal/infra/storage"
+	"hackaton-service-api/internal/media"
 	"hackaton-service-api/internal/middleware"
+	"hackaton-service-api/internal/scheduler"
 	"hackaton-service-api/internal/usecase"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 	_ "hackaton-service-api/docs"
 )
 
@@ -69,7 +81,12 @@ func main() {
 	if db == nil {
 		panic("❌ Falha crítica: Banco de dados não inicializado.")
 	}
-	db.AutoMigrate(&entity.User{}, &entity.Video{})
+	db.AutoMigrate(&entity.User{}, &entity.Video{}, &entity.UploadPart{}, &entity.Session{}, &entity.VideoStatusEvent{}, &entity.Plan{}, &entity.OutboxMessage{}, &entity.Preset{}, &entity.Batch{}, &entity.Share{})
+
+	// Cada réplica escuta o canal de status e repassa aos clientes SSE conectados nela.
+	broker := events.NewBroker()
+	listener := events.NewPostgresListener(database.BuildDSN(dbHost, dbUser, dbPassword, dbName, dbSslmode), database.VideoStatusChannel, broker)
+	go listener.Run(ctx)
 
 	storageService := service.NewStorageService(
 		awsFactory.NewS3Client(),
@@ -77,17 +94,106 @@ func main() {
 		awsBucket,
 		awsQueueURL,
 	)
+	storageService.Presign = loadPresignPolicy(awsEndpoint)
+
+	keySet, err := loadSigningKeys(awsFactory)
+	if err != nil {
+		panic(fmt.Sprintf("❌ Falha crítica: chaves JWT inválidas: %v", err))
+	}
 
-	tokenService := service.NewTokenService()
+	tokenService := service.NewTokenService(keySet)
 	videoRepo := database.NewVideoRepository(db)
 	userRepo := database.NewUserRepository(db)
+	uploadPartRepo := database.NewUploadPartRepository(db)
+	sessionRepo := database.NewSessionRepository(db)
+	planRepo := database.NewPlanRepository(db)
+	presetRepo := database.NewPresetRepository(db)
+	batchRepo := database.NewBatchRepository(db)
+	shareRepo := database.NewShareRepository(db)
+
+	if err := planRepo.EnsureExists(entity.DefaultPlan()); err != nil {
+		fmt.Printf("⚠️ Falha ao criar o plano padrão: %v\n", err)
+	}
+	if n, err := userRepo.BackfillEmailVerification(); err != nil {
+		fmt.Printf("⚠️ Falha ao confirmar o e-mail das contas antigas: %v\n", err)
+	} else if n > 0 {
+		fmt.Printf("✅ E-mail confirmado para %d contas anteriores à verificação\n", n)
+	}
+
+	fileStorage, signedStorage := loadStorage(storageService, awsBucket)
 
-	videoUC := usecase.NewVideoUseCase(videoRepo, userRepo, storageService, storageService)
-	userUC := usecase.NewUserUseCase(userRepo, tokenService)
+	queueService := loadQueue(storageService, db)
 
-	authMiddleware := middleware.NewAuthMiddleware(tokenService)
+	videoUC := usecase.NewVideoUseCase(videoRepo, userRepo, fileStorage, queueService)
+	uploadUC := usecase.NewUploadUseCase(videoRepo, userRepo, uploadPartRepo, fileStorage)
+	userUC := usecase.NewUserUseCase(userRepo, sessionRepo, tokenService)
+
+	publicURL := strings.TrimSuffix(getEnv("PUBLIC_API_URL", "http://localhost:8080"), "/")
+	userUC.Verification = tokenService
+	userUC.Mailer = loadMailer()
+	userUC.VerifyURL = publicURL + "/api/verify-email"
+
+	uploadPolicy := loadUploadPolicy()
+	videoUC.Policy = uploadPolicy
+	uploadUC.Policy = uploadPolicy
+
+	quota := usecase.NewQuotaService(planRepo, userRepo, videoRepo)
+	videoUC.Quota = quota
+	videoUC.Presets = presetRepo
+	uploadUC.Quota = quota
+
+	retention := getEnvDuration("VIDEO_RETENTION", 7*24*time.Hour)
+	go scheduler.Every(ctx, "purge", time.Hour, func() error {
+		purged, err := videoUC.PurgeDeleted(retention)
+		if purged > 0 {
+			fmt.Printf("🧹 %d vídeo(s) removido(s) definitivamente\n", purged)
+		}
+		return err
+	})
+
+	// Uploads abandonados contam como vídeos em andamento até expirarem.
+	uploadExpiry := getEnvDuration("UPLOAD_EXPIRY", 24*time.Hour)
+	go scheduler.Every(ctx, "upload-expiry", 10*time.Minute, func() error {
+		expired, err := uploadUC.ExpireAbandoned(uploadExpiry)
+		if expired > 0 {
+			fmt.Printf("⌛ %d upload(s) abandonado(s) expirado(s)\n", expired)
+		}
+		return err
+	})
+
+	videoUC.Requeue = loadRequeuePolicy()
+	go scheduler.Every(ctx, "requeue", time.Minute, func() error {
+		requeued, failed, err := videoUC.RequeueStuck()
+		if requeued > 0 || failed > 0 {
+			fmt.Printf("🔁 %d vídeo(s) parado(s) reenviado(s) à fila, %d marcado(s) com erro\n", requeued, failed)
+		}
+		return err
+	})
+
+	// Os vídeos chegam à fila pelo outbox; o relay roda em todas as réplicas e
+	// o SKIP LOCKED evita publicações duplicadas entre elas.
+	relay := usecase.NewOutboxRelay(database.NewOutboxRepository(db), queueService)
+	go scheduler.Every(ctx, "outbox", 2*time.Second, func() error {
+		_, err := relay.Relay()
+		return err
+	})
+	go scheduler.Every(ctx, "outbox-cleanup", time.Hour, func() error {
+		_, err := relay.Cleanup(7 * 24 * time.Hour)
+		return err
+	})
+
+	authMiddleware := middleware.NewAuthMiddleware(tokenService, userUC)
+	serviceMiddleware := middleware.NewServiceAuthMiddleware(getEnv("INTERNAL_API_TOKEN", ""), getEnv("INTERNAL_API_SECRET", ""))
 	videoHandler := handler.NewVideoHandler(videoUC)
+	uploadHandler := handler.NewUploadHandler(uploadUC)
 	authHandler := handler.NewAuthHandler(userUC)
+	jwksHandler := handler.NewJWKSHandler(tokenService)
+	internalHandler := handler.NewInternalHandler(videoUC)
+	eventsHandler := handler.NewEventsHandler(broker)
+	usageHandler := handler.NewUsageHandler(quota)
+	presetHandler := handler.NewPresetHandler(usecase.NewPresetUseCase(presetRepo))
+	batchHandler := handler.NewBatchHandler(usecase.NewBatchUseCase(batchRepo, videoUC))
+	shareHandler := handler.NewShareHandler(usecase.NewShareUseCase(shareRepo, videoUC), publicURL)
 
 	r := gin.Default()
 
@@ -104,8 +210,15 @@ func main() {
 		c.JSON(200, gin.H{"status": "ready", "database": "up"})
 	})
 
+	r.GET("/.well-known/jwks.json", jwksHandler.GetJWKS)
 	r.GET("/swagger-ui/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-	setupRoutes(r, authHandler, videoHandler, authMiddleware)
+	setupRoutes(r, authHandler, videoHandler, uploadHandler, eventsHandler, usageHandler, presetHandler, batchHandler, shareHandler, authMiddleware)
+	setupInternalRoutes(r, internalHandler, serviceMiddleware)
+	if signedStorage != nil {
+		storageHandler := handler.NewStorageHandler(signedStorage)
+		r.GET(strings.TrimSuffix(storage.PathPrefix, "/")+"/*key", storageHandler.Download)
+		r.PUT(strings.TrimSuffix(storage.PathPrefix, "/")+"/*key", storageHandler.Upload)
+	}
 
 	fmt.Printf("🚀 API rodando na porta 8080. Banco: %s\n", dbHost)
 	r.Run(":8080")
@@ -118,24 +231,313 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func setupRoutes(r *gin.Engine, auth *handler.AuthHandler, video *handler.VideoHandler, mid *middleware.AuthMiddleware) {
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fmt.Printf("⚠️ %s inválido (%v). Usando %s.\n", key, err, fallback)
+		return fallback
+	}
+	return d
+}
+
+// videoStorage reúne o que os use cases de vídeo e de upload usam do
+// armazenamento.
+type videoStorage interface {
+	usecase.FileStorageService
+	usecase.UploadStorageService
+}
+
+// loadStorage escolhe o backend por STORAGE_BACKEND: s3 (padrão), local
+// (diretório STORAGE_LOCAL_DIR) ou memory. Os dois últimos devolvem também o
+// serviço que atende os links assinados em /storage.
+func loadStorage(s3Storage *service.StorageService, bucket string) (videoStorage, *storage.Service) {
+	var blobs storage.Blobs
+	switch backend := getEnv("STORAGE_BACKEND", "s3"); backend {
+	case "s3":
+		return s3Storage, nil
+	case "local":
+		dir := getEnv("STORAGE_LOCAL_DIR", "./data/storage")
+		local, err := storage.NewLocalBlobs(dir)
+		if err != nil {
+			panic(fmt.Sprintf("❌ Falha crítica: diretório de armazenamento %s: %v", dir, err))
+		}
+		fmt.Printf("📦 Armazenamento local em %s\n", dir)
+		blobs = local
+	case "memory":
+		fmt.Println("📦 Armazenamento em memória (os arquivos se perdem ao reiniciar)")
+		blobs = storage.NewMemoryBlobs()
+	default:
+		panic(fmt.Sprintf("❌ Falha crítica: STORAGE_BACKEND desconhecido: %s", backend))
+	}
+
+	signer := storage.URLSigner{
+		Secret:  storageSigningSecret(),
+		BaseURL: getEnv("STORAGE_PUBLIC_URL", "http://localhost:8080"),
+		TTL:     s3Storage.Presign.Expiry,
+	}
+	signed := storage.NewService(blobs, bucket, signer)
+	signed.Disposition = s3Storage.Presign.Disposition
+	return signed, signed
+}
+
+// loadQueue escolhe a fila por QUEUE_BACKEND: sqs (padrão), postgres (tabela
+// queue_jobs, fila QUEUE_NAME) ou memory. A mensagem tem o mesmo formato em
+// todos eles.
+func loadQueue(sqs *service.StorageService, db *gorm.DB) usecase.QueueService {
+	switch backend := getEnv("QUEUE_BACKEND", "sqs"); backend {
+	case "sqs":
+		return sqs
+	case "postgres":
+		if err := queue.Migrate(db); err != nil {
+			panic(fmt.Sprintf("❌ Falha crítica: tabela da fila: %v", err))
+		}
+		name := getEnv("QUEUE_NAME", "video-processing")
+		fmt.Printf("📨 Fila no PostgreSQL (%s)\n", name)
+		return queue.NewPostgresQueue(db, name)
+	case "memory":
+		fmt.Println("📨 Fila em memória (sem consumidor externo)")
+		return queue.NewMemoryQueue(1000)
+	default:
+		panic(fmt.Sprintf("❌ Falha crítica: QUEUE_BACKEND desconhecido: %s", backend))
+	}
+}
+
+// storageSigningSecret gera um segredo efêmero quando não configurado; os
+// links deixam de valer ao reiniciar e não são aceitos por outras réplicas.
+func storageSigningSecret() []byte {
+	if secret := getEnv("STORAGE_SIGNING_SECRET", ""); secret != "" {
+		return []byte(secret)
+	}
+
+	fmt.Println("⚠️ STORAGE_SIGNING_SECRET não definido. Usando segredo efêmero.")
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("❌ Falha crítica: %v", err))
+	}
+	return secret
+}
+
+// loadUploadPolicy lê VIDEO_ALLOWED_FORMATS (ex.: "mp4,mkv"),
+// VIDEO_MAX_SIZE_MB, VIDEO_MAX_DURATION (ex.: "2h"), VIDEO_MAX_RESOLUTION
+// (ex.: "1920x1080") e REQUIRE_EMAIL_VERIFICATION; valores ausentes ou
+// inválidos mantêm o padrão.
+func loadUploadPolicy() usecase.UploadPolicy {
+	policy := usecase.DefaultUploadPolicy()
+
+	if formats := media.ParseFormats(getEnv("VIDEO_ALLOWED_FORMATS", "")); len(formats) > 0 {
+		policy.AllowedFormats = formats
+	}
+
+	if raw := getEnv("VIDEO_MAX_SIZE_MB", ""); raw != "" {
+		mb, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || mb <= 0 {
+			fmt.Printf("⚠️ VIDEO_MAX_SIZE_MB inválido (%s). Usando %d MB.\n", raw, policy.MaxSize>>20)
+		} else {
+			policy.MaxSize = mb << 20
+		}
+	}
+
+	policy.MaxDuration = getEnvDuration("VIDEO_MAX_DURATION", 0)
+
+	if raw := getEnv("VIDEO_MAX_RESOLUTION", ""); raw != "" {
+		var width, height int
+		if _, err := fmt.Sscanf(strings.ToLower(raw), "%dx%d", &width, &height); err != nil || width <= 0 || height <= 0 {
+			fmt.Printf("⚠️ VIDEO_MAX_RESOLUTION inválido (%s). Resolução sem limite.\n", raw)
+		} else {
+			policy.MaxWidth, policy.MaxHeight = width, height
+		}
+	}
+
+	if raw := getEnv("REQUIRE_EMAIL_VERIFICATION", ""); raw != "" {
+		require, err := strconv.ParseBool(raw)
+		if err != nil {
+			fmt.Printf("⚠️ REQUIRE_EMAIL_VERIFICATION inválido (%s). Exigindo e-mail confirmado.\n", raw)
+		} else {
+			policy.RequireVerifiedEmail = require
+		}
+	}
+
+	return policy
+}
+
+// loadMailer escolhe o envio de e-mails por MAIL_BACKEND: log (padrão, grava
+// em MAIL_LOG_DIR ou no log) ou smtp (SMTP_ADDR, SMTP_USERNAME,
+// SMTP_PASSWORD e SMTP_TIMEOUT). MAIL_FROM é o remetente.
+func loadMailer() usecase.Mailer {
+	from := getEnv("MAIL_FROM", "FIAP X <noreply@fiapx.local>")
+
+	switch backend := getEnv("MAIL_BACKEND", "log"); backend {
+	case "log":
+		dir := getEnv("MAIL_LOG_DIR", "")
+		if dir == "" {
+			fmt.Println("✉️ E-mails escritos no log (MAIL_BACKEND=log)")
+		} else {
+			fmt.Printf("✉️ E-mails gravados em %s (MAIL_BACKEND=log)\n", dir)
+		}
+		return mail.NewLogMailer(dir, from)
+	case "smtp":
+		mailer, err := mail.NewSMTPMailer(getEnv("SMTP_ADDR", ""), getEnv("SMTP_USERNAME", ""), getEnv("SMTP_PASSWORD", ""), from)
+		if err != nil {
+			panic(fmt.Sprintf("❌ Falha crítica: configuração SMTP: %v", err))
+		}
+		mailer.Timeout = getEnvDuration("SMTP_TIMEOUT", mailer.Timeout)
+		fmt.Printf("✉️ E-mails enviados por SMTP (%s)\n", mailer.Addr)
+		return mailer
+	default:
+		panic(fmt.Sprintf("❌ Falha crítica: MAIL_BACKEND desconhecido: %s", backend))
+	}
+}
+
+// loadRequeuePolicy lê quando um vídeo em PENDING/PROCESSING é considerado
+// parado (VIDEO_STUCK_AFTER) e quantas vezes é reenviado à fila
+// (VIDEO_REQUEUE_ATTEMPTS) antes de ficar em ERROR.
+func loadRequeuePolicy() usecase.RequeuePolicy {
+	policy := usecase.DefaultRequeuePolicy()
+	policy.StaleAfter = getEnvDuration("VIDEO_STUCK_AFTER", policy.StaleAfter)
+
+	if raw := getEnv("VIDEO_REQUEUE_ATTEMPTS", ""); raw != "" {
+		attempts, err := strconv.Atoi(raw)
+		if err != nil || attempts < 0 {
+			fmt.Printf("⚠️ VIDEO_REQUEUE_ATTEMPTS inválido (%s). Usando %d.\n", raw, policy.MaxAttempts)
+		} else {
+			policy.MaxAttempts = attempts
+		}
+	}
+
+	return policy
+}
+
+// loadPresignPolicy lê a validade dos links (PRESIGN_EXPIRY, até 7 dias como
+// no S3), o endereço do S3 usado nos links (S3_PUBLIC_ENDPOINT) e o
+// Content-Disposition dos downloads (DOWNLOAD_DISPOSITION: attachment, inline
+// ou none). Sem S3_PUBLIC_ENDPOINT, o localstack do docker compose é trocado
+// por localhost, que é por onde o navegador o alcança.
+func loadPresignPolicy(awsEndpoint string) service.PresignPolicy {
+	policy := service.DefaultPresignPolicy()
+
+	expiry := getEnvDuration("PRESIGN_EXPIRY", policy.Expiry)
+	if expiry <= 0 || expiry > 7*24*time.Hour {
+		fmt.Printf("⚠️ PRESIGN_EXPIRY fora do intervalo aceito pelo S3 (%s). Usando %s.\n", expiry, policy.Expiry)
+	} else {
+		policy.Expiry = expiry
+	}
+
+	policy.PublicEndpoint = getEnv("S3_PUBLIC_ENDPOINT", strings.Replace(awsEndpoint, "://localstack:", "://localhost:", 1))
+
+	switch disposition := getEnv("DOWNLOAD_DISPOSITION", policy.Disposition); disposition {
+	case "attachment", "inline":
+		policy.Disposition = disposition
+	case "none":
+		policy.Disposition = ""
+	default:
+		fmt.Printf("⚠️ DOWNLOAD_DISPOSITION inválido (%s). Usando %s.\n", disposition, policy.Disposition)
+	}
+
+	return policy
+}
+
+// loadSigningKeys procura as chaves JWT na ordem: Secrets Manager
+// (JWT_KEYS_SECRET_NAME), JSON em JWT_KEYS, segredo HS256 em JWT_SECRET e, por
+// último, uma chave efêmera apenas para desenvolvimento.
+func loadSigningKeys(awsFactory *service.AWSClientFactory) (*auth.KeySet, error) {
+	if secretName := getEnv("JWT_KEYS_SECRET_NAME", ""); secretName != "" {
+		cfg, err := service.GetJWTKeys(awsFactory, secretName)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Println("✅ Chaves JWT carregadas do AWS Secrets Manager")
+		return auth.NewKeySet(cfg)
+	}
+
+	if raw := getEnv("JWT_KEYS", ""); raw != "" {
+		cfg, err := auth.ParseKeySetConfig([]byte(raw))
+		if err != nil {
+			return nil, err
+		}
+		return auth.NewKeySet(cfg)
+	}
+
+	if secret := getEnv("JWT_SECRET", ""); secret != "" {
+		return auth.NewKeySet(&auth.KeySetConfig{
+			ActiveKID: "default",
+			Keys:      []auth.KeyConfig{{KID: "default", Algorithm: auth.AlgHS256, Secret: secret}},
+		})
+	}
+
+	fmt.Println("⚠️ Nenhuma chave JWT configurada. Usando chave efêmera (tokens expiram ao reiniciar).")
+	return auth.NewEphemeralKeySet()
+}
+
+func setupRoutes(r *gin.Engine, auth *handler.AuthHandler, video *handler.VideoHandler, upload *handler.UploadHandler, events *handler.EventsHandler, usage *handler.UsageHandler, presets *handler.PresetHandler, batches *handler.BatchHandler, shares *handler.ShareHandler, mid *middleware.AuthMiddleware) {
 	r.MaxMultipartMemory = 50 << 20
 	r.Static("/static", "./web")
 
 	r.GET("/", func(c *gin.Context) { c.File("./web/login.html") })
 	r.GET("/dashboard", func(c *gin.Context) { c.File("./web/upload.html") })
 
+	// Links de compartilhamento: públicos, a autorização é o próprio token.
+	r.GET(handler.SharePathPrefix+":token", shares.OpenShare)
+	r.POST(handler.SharePathPrefix+":token", shares.OpenShare)
+
 	api := r.Group("/api")
 	{
 		api.POST("/register", auth.Register)
 		api.POST("/login", auth.Login)
+		api.POST("/token/refresh", auth.Refresh)
+		api.GET("/verify-email", auth.VerifyEmail)
 
 		protected := api.Group("/")
 		protected.Use(mid.Handle())
 		{
+			protected.POST("/logout", auth.Logout)
+			protected.POST("/logout/all", auth.LogoutAll)
+			protected.GET("/sessions", auth.ListSessions)
+			protected.POST("/verify-email/resend", auth.ResendVerification)
+			protected.GET("/me/usage", usage.GetUsage)
+
 			protected.POST("/upload", video.UploadVideo)
+			protected.POST("/upload/batch", batches.UploadBatch)
+			protected.GET("/batches", batches.ListBatches)
+			protected.GET("/batches/:id", batches.GetBatch)
 			protected.GET("/videos", video.ListVideos)
+			protected.GET("/videos/events", events.StreamStatus)
+			protected.GET("/videos/archive", video.DownloadArchive)
+			protected.HEAD("/videos/archive", video.DownloadArchive)
+			protected.GET("/videos/:id", video.GetVideo)
+			protected.DELETE("/videos/:id", video.DeleteVideo)
 			protected.GET("/videos/:id/download", video.GetDownloadLink)
+			protected.GET("/videos/:id/history", video.GetHistory)
+			protected.POST("/videos/:id/retry", video.RetryVideo)
+			protected.POST("/videos/:id/shares", shares.CreateShare)
+			protected.GET("/videos/:id/shares", shares.ListShares)
+			protected.DELETE("/videos/:id/shares/:share_id", shares.RevokeShare)
+			protected.POST("/videos/presign", upload.RequestDirectUpload)
+			protected.POST("/videos/:id/complete", upload.ConfirmDirectUpload)
+
+			protected.POST("/uploads", upload.InitiateUpload)
+			protected.GET("/uploads/:id", upload.ListParts)
+			protected.PUT("/uploads/:id/parts/:part", upload.UploadPart)
+			protected.POST("/uploads/:id/complete", upload.CompleteUpload)
+			protected.DELETE("/uploads/:id", upload.AbortUpload)
+
+			protected.POST("/presets", presets.CreatePreset)
+			protected.GET("/presets", presets.ListPresets)
+			protected.GET("/presets/:id", presets.GetPreset)
+			protected.PUT("/presets/:id", presets.UpdatePreset)
+			protected.DELETE("/presets/:id", presets.DeletePreset)
 		}
 	}
+}
+
+func setupInternalRoutes(r *gin.Engine, internal *handler.InternalHandler, mid *middleware.ServiceAuthMiddleware) {
+	group := r.Group("/internal")
+	group.Use(mid.Handle())
+	{
+		group.GET("/videos/:id", internal.GetVideo)
+		group.PUT("/videos/:id/status", internal.UpdateStatus)
+	}
 }
\ No newline at end of file
@@ -15,9 +15,108 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Publica o JWKS para que o worker e outros serviços validem os tokens offline. Chaves HMAC não são publicadas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Chaves públicas de assinatura dos tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_auth.JWKS"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/batches": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batches"
+                ],
+                "summary": "Lista os lotes recentes do usuário",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/hackaton-service-api_internal_entity.Batch"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/batches/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Traz os vídeos do lote, a contagem por status e finished, verdadeiro quando nenhum vídeo ainda está em andamento.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batches"
+                ],
+                "summary": "Progresso de um lote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do lote",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_usecase.BatchDetail"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Lote não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
         "/api/login": {
             "post": {
-                "description": "Autentica o usuário e retorna um token JWT",
+                "description": "Autentica o usuário e retorna um token JWT de curta duração e um refresh token para renová-lo",
                 "consumes": [
                     "application/json"
                 ],
@@ -43,19 +142,18 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_handler.TokenResponse"
                         }
                     }
                 }
             }
         },
-        "/api/register": {
+        "/api/logout": {
             "post": {
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
                 "produces": [
                     "application/json"
@@ -63,21 +161,38 @@ const docTemplate = `{
                 "tags": [
                     "Auth"
                 ],
-                "summary": "Registra um novo usuário",
-                "parameters": [
-                    {
-                        "description": "Dados do usuário",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                "summary": "Encerra a sessão atual",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/internal_handler.RegisterRequest"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
+                }
+            }
+        },
+        "/api/logout/all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Revoga os refresh tokens de todos os dispositivos; os tokens de acesso emitidos deixam de ser aceitos imediatamente.",
+                "produces": [
+                    "application/json"
                 ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Encerra todas as sessões do usuário",
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -88,36 +203,103 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/upload": {
+        "/api/me/usage": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Armazenamento ocupado pelos vídeos (enviado + ZIP), vídeos em andamento e envios na última hora. Limite 0 significa ilimitado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Consumo do usuário em relação aos limites do plano",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_usecase.Usage"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/presets": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Presets"
+                ],
+                "summary": "Lista os presets do usuário",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/hackaton-service-api_internal_entity.Preset"
+                            }
+                        }
+                    }
+                }
+            },
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Faz o upload de um arquivo de vídeo para processamento",
+                "description": "Grava um conjunto nomeado de opções de extração. Opções omitidas recebem o padrão do sistema. Com is_default, o preset passa a ser usado nos uploads sem preset_id e deixa de haver outro padrão.",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Videos"
+                    "Presets"
                 ],
-                "summary": "Realiza o upload de um vídeo",
+                "summary": "Cria um preset de processamento",
                 "parameters": [
                     {
-                        "type": "file",
-                        "description": "Arquivo de vídeo (.mp4, .mkv, .avi)",
-                        "name": "video",
-                        "in": "formData",
-                        "required": true
+                        "description": "Nome e opções",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PresetRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Accepted",
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.Preset"
+                        }
+                    },
+                    "400": {
+                        "description": "Opções inválidas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Nome já usado",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -128,61 +310,100 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/videos": {
+        "/api/presets/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna todos os vídeos enviados pelo usuário logado",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Videos"
+                    "Presets"
+                ],
+                "summary": "Detalhe de um preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do preset",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Lista vídeos do usuário",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/hackaton-service-api_internal_entity.Video"
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.Preset"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Preset não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/api/videos/{id}/download": {
-            "get": {
+            },
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Retorna uma URL assinada do S3 para baixar o arquivo ZIP com os frames. O vídeo deve estar com status 'DONE'.",
+                "description": "Substitui nome, opções e is_default. Vídeos já enviados mantêm as opções com que foram enfileirados.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Videos"
+                    "Presets"
                 ],
-                "summary": "Gera link para download do vídeo processado",
+                "summary": "Atualiza um preset",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do Vídeo",
+                        "description": "ID do preset",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Nome e opções",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.PresetRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "link: http://s3.url...",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.Preset"
+                        }
+                    },
+                    "400": {
+                        "description": "Opções inválidas",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -190,7 +411,7 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
+                    "403": {
                         "description": "Acesso negado",
                         "schema": {
                             "type": "object",
@@ -200,7 +421,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Vídeo não encontrado",
+                        "description": "Preset não encontrado",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -208,8 +429,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "422": {
-                        "description": "Vídeo não está pronto",
+                    "409": {
+                        "description": "Nome já usado",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -218,62 +439,2356 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Presets"
+                ],
+                "summary": "Remove um preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do preset",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Preset não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/register": {
+            "post": {
+                "description": "Envia um link de confirmação para o e-mail informado; o envio de vídeos fica bloqueado até a confirmação. Se o e-mail não puder ser enviado, a conta é criada mesmo assim e o link pode ser reenviado depois do login.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Registra um novo usuário",
+                "parameters": [
+                    {
+                        "description": "Dados do usuário",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Lista as sessões ativas do usuário",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/hackaton-service-api_internal_entity.Session"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/token/refresh": {
+            "post": {
+                "description": "Troca o refresh token por um novo par de tokens. O refresh token anterior deixa de valer; reutilizá-lo encerra a sessão.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Renova o token de acesso",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.TokenResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Sessão inválida ou expirada",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/upload": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Faz o upload de um arquivo de vídeo para processamento. O formato é identificado pelo conteúdo (MP4, MKV ou AVI), não pela extensão. As opções de processamento são opcionais: as informadas têm prioridade sobre as do preset (preset_id ou, sem ele, o preset padrão do usuário), e as restantes usam o padrão do sistema (1 frame por segundo, PNG, sem limite de frames).",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Realiza o upload de um vídeo",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Arquivo de vídeo (.mp4, .mkv, .avi)",
+                        "name": "video",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de um preset do usuário",
+                        "name": "preset_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Segundos entre frames extraídos (0.1 a 3600)",
+                        "name": "frame_interval",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato dos frames: png ou jpeg",
+                        "name": "output_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de frames extraídos (0 = sem limite, até 10000)",
+                        "name": "max_frames",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Opções de processamento inválidas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Cota de armazenamento do plano excedida ou e-mail não confirmado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Preset não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Arquivo maior que o permitido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Duração ou resolução acima do limite",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de vídeos em andamento ou de envios por hora atingido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/upload/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Envia até 20 arquivos no campo videos, com as mesmas validações e opções de processamento do upload individual, aplicadas a todos. A recusa de um arquivo não impede os demais: cada item da resposta traz o vídeo criado ou o erro e o status HTTP correspondente. As cotas do plano valem para o lote inteiro: os arquivos além do limite de vídeos em andamento, de envios por hora ou de espaço são recusados sem envio, e o lote todo é recusado quando nenhum cabe.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Batches"
+                ],
+                "summary": "Realiza o upload de vários vídeos",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Arquivos de vídeo (repita o campo para cada arquivo)",
+                        "name": "videos",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome do lote",
+                        "name": "name",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID de um preset do usuário",
+                        "name": "preset_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Segundos entre frames extraídos (0.1 a 3600)",
+                        "name": "frame_interval",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Formato dos frames: png ou jpeg",
+                        "name": "output_format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de frames extraídos (0 = sem limite, até 10000)",
+                        "name": "max_frames",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Nenhum arquivo, arquivos demais ou opções inválidas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "E-mail não confirmado ou cota de armazenamento do plano excedida",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Preset não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de vídeos em andamento ou de envios por hora atingido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria o vídeo em estado UPLOADING e abre um upload multipart no S3. As partes são enviadas em seguida e o processamento só é disparado no complete.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Inicia um upload em partes",
+                "parameters": [
+                    {
+                        "description": "Nome do arquivo (.mp4, .mkv, .avi)",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.InitiateUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Cota de armazenamento do plano excedida ou e-mail não confirmado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de vídeos em andamento ou de envios por hora atingido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploads/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Usado pelo cliente para descobrir de qual parte retomar o upload.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Lista as partes já recebidas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/hackaton-service-api_internal_entity.UploadPart"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Cancela um upload em partes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploads/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Monta o objeto no S3 a partir das partes enviadas e envia o vídeo para a fila de processamento.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Finaliza o upload em partes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Duração ou resolução acima do limite",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/uploads/{id}/parts/{part}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Recebe o corpo bruto da parte, com Content-Length obrigatório. Toda parte, exceto a última, precisa ter ao menos min_part_size bytes. Reenviar o mesmo número substitui a parte anterior, permitindo retomar após falhas.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Envia uma parte do upload",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Número da parte (1-10000)",
+                        "name": "part",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.UploadPart"
+                        }
+                    },
+                    "400": {
+                        "description": "Parte vazia ou parte intermediária menor que o mínimo",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Cota de armazenamento do plano excedida",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "411": {
+                        "description": "Content-Length não informado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Parte maior que o permitido ou limite do arquivo excedido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Conteúdo da primeira parte não é um vídeo aceito",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/verify-email": {
+            "get": {
+                "description": "Rota pública aberta pelo link enviado por e-mail. O link vale por 48 horas e só para o e-mail para o qual foi enviado. Confirmar de novo não é erro.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Confirma o e-mail do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token de confirmação",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Link inválido ou expirado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/verify-email/resend": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Os links enviados antes continuam valendo até expirar. Um novo envio só é aceito depois de um intervalo mínimo desde o anterior.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Reenvia o e-mail de confirmação",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "E-mail já confirmado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "E-mail de confirmação enviado há pouco tempo",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "502": {
+                        "description": "Falha no envio do e-mail",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os vídeos do usuário logado em páginas. Para a próxima página, repita a chamada com o next_cursor recebido (e os mesmos filtros). O total de vídeos que atendem aos filtros vem no header X-Total-Count.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Lista vídeos do usuário",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Status separados por vírgula (ex.: PENDING,PROCESSING)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados a partir de (RFC 3339 ou AAAA-MM-DD, inclusivo)",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Criados antes de (RFC 3339 ou AAAA-MM-DD, exclusivo)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trecho do nome do arquivo",
+                        "name": "file_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Somente vídeos do lote",
+                        "name": "batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "-created_at",
+                            "created_at",
+                            "-updated_at",
+                            "updated_at"
+                        ],
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Ordenação",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Itens por página (máx. 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor devolvido na página anterior",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_usecase.VideoPage"
+                        },
+                        "headers": {
+                            "X-Total-Count": {
+                                "type": "integer",
+                                "description": "Total de vídeos que atendem aos filtros"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Parâmetros inválidos",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/archive": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Monta sob demanda um ZIP com os ZIPs de frames dos vídeos informados em ids (separados por vírgula, todos em DONE, até 50) ou dos vídeos em DONE do lote batch_id. O conteúdo é transmitido sem ficar em memória. O arquivo é o mesmo enquanto os vídeos não mudarem, então aceita Range e If-Range para retomar downloads interrompidos.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Baixa vários vídeos processados em um único ZIP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "IDs dos vídeos, separados por vírgula",
+                        "name": "ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do lote",
+                        "name": "batch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trecho a retomar (ex.: bytes=1048576-)",
+                        "name": "Range",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "206": {
+                        "description": "Trecho pedido em Range",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Nenhum vídeo informado, ids e batch_id juntos ou vídeos demais",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado ou lote sem vídeos prontos",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Vídeo não está pronto",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "416": {
+                        "description": "Range fora do arquivo",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mantém uma conexão Server-Sent Events e envia um evento \"status\" a cada transição de um vídeo do usuário. Eventos emitidos enquanto o cliente estava desconectado não são reenviados; ao reconectar, recarregue a lista.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Stream de mudanças de status dos vídeos",
+                "responses": {
+                    "200": {
+                        "description": "event: status",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatusChange"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/presign": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria o vídeo em estado UPLOADING e devolve uma URL de PUT que expira conforme PRESIGN_EXPIRY (padrão de 15 minutos). O PUT deve usar exatamente o Content-Type e o tamanho informados. Depois do envio, chame /api/videos/{id}/complete.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Gera URL pré-assinada para upload direto ao S3",
+                "parameters": [
+                    {
+                        "description": "Dados do arquivo",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.DirectUploadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Cota de armazenamento do plano excedida ou e-mail não confirmado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Arquivo maior que o permitido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Formato não aceito",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de vídeos em andamento ou de envios por hora atingido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o vídeo com os metadados de processamento: tamanho e tipo do arquivo enviado, duração do processamento, tamanho do ZIP e quantidade de frames informados pelo worker, e se o download já está disponível.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Detalhe de um vídeo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_usecase.VideoDetail"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancela o vídeo se ainda estiver em UPLOADING ou PENDING (o worker passa a ignorá-lo) e o remove da listagem. O arquivo enviado e o ZIP são apagados do S3 após a janela de retenção. Vídeos em PROCESSING não podem ser removidos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Remove um vídeo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Vídeo em processamento",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}/complete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Verifica que o arquivo existe no bucket, confere pelo conteúdo que é um vídeo aceito e envia o vídeo para a fila de processamento.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Uploads"
+                ],
+                "summary": "Confirma o upload direto ao S3",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Conteúdo não é um vídeo aceito",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "422": {
+                        "description": "Duração ou resolução acima do limite",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}/download": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna uma URL assinada do S3 para baixar o arquivo ZIP com os frames, salvo com o nome do vídeo enviado. O vídeo deve estar com status 'DONE'. Com redirect=true responde 302 para a URL, para download direto pelo navegador ou por curl -L.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Gera link para download do vídeo processado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Redireciona (302) para a URL em vez de devolvê-la",
+                        "name": "redirect",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "link: http://s3.url...",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "302": {
+                        "description": "Redirecionamento para a URL assinada"
+                    },
+                    "401": {
+                        "description": "Token ausente ou inválido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Vídeo não está pronto",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}/history": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna todas as transições de status do vídeo, com data, autor e motivo.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Histórico de status do vídeo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatusEvent"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}/retry": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devolve à fila um vídeo em ERROR usando o arquivo já enviado, sem novo upload. O status volta para PENDING, a mensagem de erro é limpa e retry_attempts é incrementado.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Videos"
+                ],
+                "summary": "Reprocessa um vídeo com erro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.Video"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Vídeo não está em ERROR",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Arquivo original não está mais disponível",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Limite de vídeos em andamento atingido",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}/shares": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Inclui os revogados e expirados, com a contagem de downloads de cada um.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shares"
+                ],
+                "summary": "Lista os links de um vídeo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_handler.ShareResponse"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gera um link /s/{token} para baixar o ZIP de frames sem conta. O token aparece só nesta resposta. Validade padrão de 7 dias (máximo de 30), max_downloads 0 sem limite e senha opcional. O vídeo deve estar com status 'DONE'.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shares"
+                ],
+                "summary": "Cria um link público de download",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Validade, limite de downloads e senha",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.CreateShareRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ShareResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Opções inválidas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Vídeo não está pronto",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/videos/{id}/shares/{share_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "O link deixa de funcionar imediatamente; links pré-assinados já entregues valem até expirar.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shares"
+                ],
+                "summary": "Revoga um link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do link",
+                        "name": "share_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.ShareResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Acesso negado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo ou link não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/videos/{id}": {
+            "get": {
+                "description": "Rota interna do worker. Autenticação por token de serviço (Authorization: Bearer) ou por X-Timestamp + X-Signature (HMAC-SHA256).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Internal"
+                ],
+                "summary": "Consulta um vídeo para processamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.Video"
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/internal/videos/{id}/status": {
+            "put": {
+                "description": "Rota interna do worker. Transições permitidas: PENDING -> PROCESSING | ERROR, PROCESSING -> DONE | ERROR. PROCESSING enviado para um vídeo já em PROCESSING é um heartbeat: evita que o vídeo seja considerado parado e reenviado à fila. DONE exige output_key (output_size e frame_count são opcionais) e ERROR exige error_message.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Internal"
+                ],
+                "summary": "Atualiza o status de processamento de um vídeo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do Vídeo",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Novo status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_handler.StatusUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.Video"
+                        }
+                    },
+                    "404": {
+                        "description": "Vídeo não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Transição de status inválida ou atualização concorrente",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/s/{token}": {
+            "get": {
+                "description": "Rota pública, sem JWT. Redireciona para o link pré-assinado do ZIP e conta um download. Links com senha aceitam o cabeçalho X-Share-Password ou, por POST, o campo password de um formulário.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shares"
+                ],
+                "summary": "Abre um link público de download",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token do link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Senha do link",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Senha do link (POST)",
+                        "name": "password",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirecionamento para o link pré-assinado"
+                    },
+                    "401": {
+                        "description": "Senha ausente ou incorreta",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Link revogado, expirado ou com downloads esgotados",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Link bloqueado por senhas incorretas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Rota pública, sem JWT. Redireciona para o link pré-assinado do ZIP e conta um download. Links com senha aceitam o cabeçalho X-Share-Password ou, por POST, o campo password de um formulário.",
+                "consumes": [
+                    "application/x-www-form-urlencoded"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Shares"
+                ],
+                "summary": "Abre um link público de download",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token do link",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Senha do link",
+                        "name": "X-Share-Password",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Senha do link (POST)",
+                        "name": "password",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "302": {
+                        "description": "Redirecionamento para o link pré-assinado"
+                    },
+                    "401": {
+                        "description": "Senha ausente ou incorreta",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Link não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "410": {
+                        "description": "Link revogado, expirado ou com downloads esgotados",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Link bloqueado por senhas incorretas",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/{key}": {
+            "get": {
+                "description": "Usado apenas com STORAGE_BACKEND local ou memory. O link é gerado pela API (ex.: /api/videos/{id}/download) e expira conforme PRESIGN_EXPIRY (padrão de 15 minutos). Suporta Range e repete o response-content-disposition do link no Content-Disposition.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Baixa um objeto por link assinado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave do objeto",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Validade (unix)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Assinatura HMAC",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "403": {
+                        "description": "Link inválido ou expirado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Objeto não encontrado",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Equivalente ao PUT na URL pré-assinada do S3 quando STORAGE_BACKEND é local ou memory. Content-Type e Content-Length devem ser os informados ao gerar o link.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Envia um objeto por link assinado",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Chave do objeto",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Validade (unix)",
+                        "name": "expires",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Assinatura HMAC",
+                        "name": "signature",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "403": {
+                        "description": "Link inválido, expirado ou com Content-Type/tamanho diferentes",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "hackaton-service-api_internal_auth.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_auth.JWKS": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/hackaton-service-api_internal_auth.JWK"
+                    }
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.Batch": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "file_count": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.Preset": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.ProcessingOptions"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.ProcessingOptions": {
+            "type": "object",
+            "properties": {
+                "frame_interval_seconds": {
+                    "type": "number"
+                },
+                "max_frames": {
+                    "type": "integer"
+                },
+                "output_format": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.Session": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "ip_address": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.UploadPart": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "etag": {
+                    "type": "string"
+                },
+                "part_number": {
+                    "type": "integer"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
         "hackaton-service-api_internal_entity.Video": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
-                },
+                "batch_id": {
+                    "type": "string"
+                },
+                "bitrate": {
+                    "type": "integer"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "correlation_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_seconds": {
+                    "description": "Metadados lidos do contêiner no upload; ficam zerados quando não foi\npossível extraí-los (ex.: AVI).",
+                    "type": "number"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "frame_count": {
+                    "type": "integer"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "input_bucket": {
+                    "type": "string"
+                },
+                "input_key": {
+                    "type": "string"
+                },
+                "input_size": {
+                    "type": "integer"
+                },
+                "options": {
+                    "description": "Opções de extração pedidas no upload (e o preset de onde vieram) e o\nidentificador que acompanha a mensagem da fila e os logs do worker; um\nreprocessamento gera outro.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.ProcessingOptions"
+                        }
+                    ]
+                },
+                "output_bucket": {
+                    "type": "string"
+                },
+                "output_key": {
+                    "type": "string"
+                },
+                "output_size": {
+                    "type": "integer"
+                },
+                "preset_id": {
+                    "type": "string"
+                },
+                "processing_finished_at": {
+                    "type": "string"
+                },
+                "processing_started_at": {
+                    "type": "string"
+                },
+                "requeue_attempts": {
+                    "description": "Reenvios automáticos à fila de um vídeo parado em PENDING ou\nPROCESSING desde o último sinal do worker; NextRequeueAt é o backoff até\no próximo. ActivityAt é a entrada no status atual ou o último heartbeat\ndo worker, e os reenvios não o alteram.",
+                    "type": "integer"
+                },
+                "retry_attempts": {
+                    "description": "Reprocessamentos pedidos pelo usuário depois de um ERROR.",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "video_codec": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.VideoStatus": {
+            "type": "string",
+            "enum": [
+                "UPLOADING",
+                "PENDING",
+                "PROCESSING",
+                "DONE",
+                "ERROR",
+                "CANCELED"
+            ],
+            "x-enum-varnames": [
+                "StatusUploading",
+                "StatusPending",
+                "StatusProcessing",
+                "StatusDone",
+                "StatusError",
+                "StatusCanceled"
+            ]
+        },
+        "hackaton-service-api_internal_entity.VideoStatusChange": {
+            "type": "object",
+            "properties": {
                 "error_message": {
                     "type": "string"
                 },
                 "file_name": {
                     "type": "string"
                 },
-                "id": {
+                "from_status": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
+                },
+                "occurred_at": {
                     "type": "string"
                 },
-                "input_bucket": {
+                "status": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
+                },
+                "user_id": {
                     "type": "string"
                 },
-                "input_key": {
+                "video_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_entity.VideoStatusEvent": {
+            "type": "object",
+            "properties": {
+                "actor": {
                     "type": "string"
                 },
-                "output_bucket": {
+                "created_at": {
                     "type": "string"
                 },
-                "output_key": {
+                "from_status": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
+                },
+                "id": {
                     "type": "string"
                 },
-                "status": {
+                "reason": {
+                    "type": "string"
+                },
+                "to_status": {
                     "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
                 },
-                "updated_at": {
+                "video_id": {
                     "type": "string"
+                }
+            }
+        },
+        "internal_handler.CreateShareRequest": {
+            "type": "object",
+            "properties": {
+                "expires_in_hours": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "max_downloads": {
+                    "type": "integer"
+                },
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "hackaton-service-api_internal_entity.VideoStatus": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "PROCESSING",
-                "DONE",
-                "ERROR"
+        "internal_handler.DirectUploadRequest": {
+            "type": "object",
+            "required": [
+                "content_type",
+                "file_name",
+                "size"
             ],
-            "x-enum-varnames": [
-                "StatusPending",
-                "StatusProcessing",
-                "StatusDone",
-                "StatusError"
-            ]
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_handler.InitiateUploadRequest": {
+            "type": "object",
+            "required": [
+                "file_name"
+            ],
+            "properties": {
+                "file_name": {
+                    "type": "string"
+                }
+            }
         },
         "internal_handler.LoginRequest": {
             "type": "object",
@@ -290,6 +2805,34 @@ const docTemplate = `{
                 }
             }
         },
+        "internal_handler.PresetRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "is_default": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.ProcessingOptions"
+                }
+            }
+        },
+        "internal_handler.RefreshRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
         "internal_handler.RegisterRequest": {
             "type": "object",
             "required": [
@@ -308,6 +2851,275 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "internal_handler.ShareResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_count": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "has_password": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_download_at": {
+                    "type": "string"
+                },
+                "max_downloads": {
+                    "type": "integer"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "video_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_handler.StatusUpdateRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "error_message": {
+                    "type": "string"
+                },
+                "frame_count": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "output_bucket": {
+                    "type": "string"
+                },
+                "output_key": {
+                    "type": "string"
+                },
+                "output_size": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "status": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
+                }
+            }
+        },
+        "internal_handler.TokenResponse": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "hackaton-service-api_internal_usecase.BatchDetail": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "file_count": {
+                    "type": "integer"
+                },
+                "finished": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status_counts": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "videos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/hackaton-service-api_internal_entity.Video"
+                    }
+                }
+            }
+        },
+        "hackaton-service-api_internal_usecase.Usage": {
+            "type": "object",
+            "properties": {
+                "plan": {
+                    "type": "string"
+                },
+                "storage_bytes": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_usecase.UsageMetric"
+                },
+                "uploads_last_hour": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_usecase.UsageMetric"
+                },
+                "videos_in_flight": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_usecase.UsageMetric"
+                }
+            }
+        },
+        "hackaton-service-api_internal_usecase.UsageMetric": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "used": {
+                    "type": "integer"
+                }
+            }
+        },
+        "hackaton-service-api_internal_usecase.VideoDetail": {
+            "type": "object",
+            "properties": {
+                "batch_id": {
+                    "type": "string"
+                },
+                "bitrate": {
+                    "type": "integer"
+                },
+                "content_type": {
+                    "type": "string"
+                },
+                "correlation_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "download_available": {
+                    "type": "boolean"
+                },
+                "duration_seconds": {
+                    "description": "Metadados lidos do contêiner no upload; ficam zerados quando não foi\npossível extraí-los (ex.: AVI).",
+                    "type": "number"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "frame_count": {
+                    "type": "integer"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "input_bucket": {
+                    "type": "string"
+                },
+                "input_key": {
+                    "type": "string"
+                },
+                "input_size": {
+                    "type": "integer"
+                },
+                "options": {
+                    "description": "Opções de extração pedidas no upload (e o preset de onde vieram) e o\nidentificador que acompanha a mensagem da fila e os logs do worker; um\nreprocessamento gera outro.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/hackaton-service-api_internal_entity.ProcessingOptions"
+                        }
+                    ]
+                },
+                "output_bucket": {
+                    "type": "string"
+                },
+                "output_key": {
+                    "type": "string"
+                },
+                "output_size": {
+                    "type": "integer"
+                },
+                "preset_id": {
+                    "type": "string"
+                },
+                "processing_duration_seconds": {
+                    "type": "number"
+                },
+                "processing_finished_at": {
+                    "type": "string"
+                },
+                "processing_started_at": {
+                    "type": "string"
+                },
+                "requeue_attempts": {
+                    "description": "Reenvios automáticos à fila de um vídeo parado em PENDING ou\nPROCESSING desde o último sinal do worker; NextRequeueAt é o backoff até\no próximo. ActivityAt é a entrada no status atual ou o último heartbeat\ndo worker, e os reenvios não o alteram.",
+                    "type": "integer"
+                },
+                "retry_attempts": {
+                    "description": "Reprocessamentos pedidos pelo usuário depois de um ERROR.",
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/hackaton-service-api_internal_entity.VideoStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                },
+                "video_codec": {
+                    "type": "string"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "hackaton-service-api_internal_usecase.VideoPage": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/hackaton-service-api_internal_entity.Video"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
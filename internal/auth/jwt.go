@@ -1,34 +1,88 @@
 package auth
 
 import (
+	"errors"
 	"time"
+
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var secretKey = []byte("SEGREDO_SUPER_SECRETO_DO_HACKATHON")
+// AccessTokenTTL é curto porque a renovação é feita com o refresh token.
+const AccessTokenTTL = 15 * time.Minute
 
-func GenerateToken(userID string) (string, error) {
+func GenerateToken(keys *KeySet, userID, sessionID string) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
 		"user_id": userID,
-		"exp":     time.Now().Add(time.Hour * 24).Unix(), // Token de 24 horas
+		"sid":     sessionID,
+		"iat":     now.Unix(),
+		"exp":     now.Add(AccessTokenTTL).Unix(),
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
+	return keys.Sign(claims)
 }
 
-func ValidateToken(tokenString string) (string, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return secretKey, nil
-	})
-	
-	if err != nil || !token.Valid {
-		return "", err
+func ValidateToken(keys *KeySet, tokenString string) (string, string, error) {
+	claims := jwt.MapClaims{}
+	token, err := keys.Parse(tokenString, claims)
+	if err != nil {
+		return "", "", err
+	}
+	if !token.Valid {
+		return "", "", errors.New("token inválido")
 	}
 
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return "", err
+	// Tokens de acesso não têm audiência: qualquer outro token assinado com as
+	// mesmas chaves, como o de confirmação de e-mail, é recusado aqui.
+	if _, ok := claims["aud"]; ok {
+		return "", "", errors.New("token não é de acesso")
 	}
 
-	return claims["user_id"].(string), nil
-}
\ No newline at end of file
+	userID, _ := claims["user_id"].(string)
+	sessionID, _ := claims["sid"].(string)
+	if userID == "" || sessionID == "" {
+		return "", "", errors.New("claims inválidas")
+	}
+
+	return userID, sessionID, nil
+}
+
+// EmailVerificationTTL é a validade do link de confirmação de e-mail.
+const EmailVerificationTTL = 48 * time.Hour
+
+// AudienceVerifyEmail identifica os tokens de confirmação de e-mail. Como as
+// chaves são as mesmas dos tokens de acesso e a JWKS é pública, quem valida
+// tokens da API precisa recusar essa audiência.
+const AudienceVerifyEmail = "verify-email"
+
+// GenerateEmailVerificationToken assina o ID e o e-mail do usuário com a
+// audiência AudienceVerifyEmail, recusada por ValidateToken.
+func GenerateEmailVerificationToken(keys *KeySet, userID, email string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"user_id": userID,
+		"email":   email,
+		"aud":     AudienceVerifyEmail,
+		"iat":     now.Unix(),
+		"exp":     now.Add(EmailVerificationTTL).Unix(),
+	}
+	return keys.Sign(claims)
+}
+
+func ValidateEmailVerificationToken(keys *KeySet, tokenString string) (string, string, error) {
+	claims := jwt.MapClaims{}
+	token, err := keys.Parse(tokenString, claims, jwt.WithAudience(AudienceVerifyEmail))
+	if err != nil {
+		return "", "", err
+	}
+	if !token.Valid {
+		return "", "", errors.New("token inválido")
+	}
+
+	userID, _ := claims["user_id"].(string)
+	email, _ := claims["email"].(string)
+	if userID == "" || email == "" {
+		return "", "", errors.New("claims inválidas")
+	}
+
+	return userID, email, nil
+}
@@ -0,0 +1,135 @@
+package auth_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"hackaton-service-api/internal/auth"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ed25519PEM(t *testing.T) (string, string) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privDER, _ := x509.MarshalPKCS8PrivateKey(priv)
+	pubDER, _ := x509.MarshalPKIXPublicKey(pub)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	oldPriv, oldPub := ed25519PEM(t)
+	newPriv, _ := ed25519PEM(t)
+
+	oldSet, err := auth.NewKeySet(&auth.KeySetConfig{
+		ActiveKID: "k1",
+		Keys:      []auth.KeyConfig{{KID: "k1", Algorithm: auth.AlgEdDSA, PrivateKey: oldPriv}},
+	})
+	require.NoError(t, err)
+
+	oldToken, err := auth.GenerateToken(oldSet, "u1", "s1")
+	require.NoError(t, err)
+
+	rotated, err := auth.NewKeySet(&auth.KeySetConfig{
+		ActiveKID: "k2",
+		Keys: []auth.KeyConfig{
+			{KID: "k2", Algorithm: auth.AlgEdDSA, PrivateKey: newPriv},
+			{KID: "k1", Algorithm: auth.AlgEdDSA, PublicKey: oldPub},
+		},
+	})
+	require.NoError(t, err)
+
+	userID, sessionID, err := auth.ValidateToken(rotated, oldToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", userID)
+	assert.Equal(t, "s1", sessionID)
+	assert.Len(t, rotated.JWKS().Keys, 2)
+}
+
+func TestKeySet_RejectsAlgorithmMismatch(t *testing.T) {
+	priv, pub := ed25519PEM(t)
+	ks, err := auth.NewKeySet(&auth.KeySetConfig{
+		ActiveKID: "k1",
+		Keys:      []auth.KeyConfig{{KID: "k1", Algorithm: auth.AlgEdDSA, PrivateKey: priv}},
+	})
+	require.NoError(t, err)
+
+	// Token HS256 assinado com a chave pública como segredo, usando o kid da chave EdDSA.
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": "admin", "sid": "s1", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	forged.Header["kid"] = "k1"
+	signed, err := forged.SignedString([]byte(pub))
+	require.NoError(t, err)
+
+	_, _, err = auth.ValidateToken(ks, signed)
+	assert.Error(t, err)
+}
+
+func TestKeySet_RejectsUnknownKID(t *testing.T) {
+	secret := "0123456789abcdef0123456789abcdef"
+	ks, err := auth.NewKeySet(&auth.KeySetConfig{
+		ActiveKID: "h1",
+		Keys:      []auth.KeyConfig{{KID: "h1", Algorithm: auth.AlgHS256, Secret: secret}},
+	})
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id": "u1", "sid": "s1", "exp": time.Now().Add(time.Hour).Unix(),
+	})
+	token.Header["kid"] = "outra"
+	signed, _ := token.SignedString([]byte(secret))
+
+	_, _, err = auth.ValidateToken(ks, signed)
+	assert.Error(t, err)
+	assert.Empty(t, ks.JWKS().Keys)
+}
+
+func TestNewKeySet_RejectsShortSecret(t *testing.T) {
+	_, err := auth.NewKeySet(&auth.KeySetConfig{
+		ActiveKID: "h1",
+		Keys:      []auth.KeyConfig{{KID: "h1", Algorithm: auth.AlgHS256, Secret: "curto"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestEmailVerificationToken(t *testing.T) {
+	ks, err := auth.NewKeySet(&auth.KeySetConfig{
+		ActiveKID: "h1",
+		Keys:      []auth.KeyConfig{{KID: "h1", Algorithm: auth.AlgHS256, Secret: "0123456789abcdef0123456789abcdef"}},
+	})
+	require.NoError(t, err)
+
+	token, err := auth.GenerateEmailVerificationToken(ks, "u1", "ana@example.com")
+	require.NoError(t, err)
+
+	userID, email, err := auth.ValidateEmailVerificationToken(ks, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", userID)
+	assert.Equal(t, "ana@example.com", email)
+
+	// Um não serve no lugar do outro.
+	_, _, err = auth.ValidateToken(ks, token)
+	assert.Error(t, err)
+	access, err := auth.GenerateToken(ks, "u1", "s1")
+	require.NoError(t, err)
+	_, _, err = auth.ValidateEmailVerificationToken(ks, access)
+	assert.Error(t, err)
+
+	// A audiência basta para recusar, mesmo com os claims de um token de acesso.
+	withSession, err := ks.Sign(jwt.MapClaims{
+		"user_id": "u1",
+		"sid":     "s1",
+		"aud":     auth.AudienceVerifyEmail,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	require.NoError(t, err)
+	_, _, err = auth.ValidateToken(ks, withSession)
+	assert.EqualError(t, err, "token não é de acesso")
+}
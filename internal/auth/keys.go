@@ -0,0 +1,278 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// Segredos HMAC menores que isso são rejeitados na carga da configuração.
+const minSecretLength = 32
+
+// KeyConfig descreve uma chave no formato aceito em JWT_KEYS ou no Secrets
+// Manager. Chaves só com public_key servem apenas para validar tokens
+// emitidos antes de uma rotação.
+type KeyConfig struct {
+	KID        string `json:"kid"`
+	Algorithm  string `json:"alg"`
+	Secret     string `json:"secret,omitempty"`
+	PrivateKey string `json:"private_key,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+}
+
+type KeySetConfig struct {
+	ActiveKID string      `json:"active_kid"`
+	Keys      []KeyConfig `json:"keys"`
+}
+
+type Key struct {
+	ID        string
+	Algorithm string
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+}
+
+func (k *Key) canSign() bool {
+	return k.signKey != nil
+}
+
+// KeySet assina com a chave ativa e valida com qualquer chave conhecida,
+// escolhida pelo header kid do token.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+}
+
+func ParseKeySetConfig(raw []byte) (*KeySetConfig, error) {
+	var cfg KeySetConfig
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("configuração de chaves JWT inválida: %w", err)
+	}
+	return &cfg, nil
+}
+
+func NewKeySet(cfg *KeySetConfig) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key)}
+
+	for _, kc := range cfg.Keys {
+		if kc.KID == "" {
+			return nil, errors.New("chave JWT sem kid")
+		}
+		if _, dup := ks.keys[kc.KID]; dup {
+			return nil, fmt.Errorf("kid duplicado: %s", kc.KID)
+		}
+
+		key, err := parseKey(kc)
+		if err != nil {
+			return nil, fmt.Errorf("chave %s: %w", kc.KID, err)
+		}
+		ks.keys[kc.KID] = key
+	}
+
+	active, ok := ks.keys[cfg.ActiveKID]
+	if !ok {
+		return nil, fmt.Errorf("active_kid %q não encontrado", cfg.ActiveKID)
+	}
+	if !active.canSign() {
+		return nil, fmt.Errorf("chave ativa %s não possui chave privada", active.ID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// NewEphemeralKeySet gera uma chave EdDSA em memória. Serve apenas para
+// desenvolvimento: os tokens deixam de valer quando o processo reinicia.
+func NewEphemeralKeySet() (*KeySet, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{
+		ID:        "ephemeral",
+		Algorithm: AlgEdDSA,
+		method:    jwt.SigningMethodEdDSA,
+		signKey:   priv,
+		verifyKey: pub,
+	}
+	return &KeySet{active: key, keys: map[string]*Key{key.ID: key}}, nil
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.active.method, claims)
+	token.Header["kid"] = ks.active.ID
+	return token.SignedString(ks.active.signKey)
+}
+
+// Parse exige kid conhecido e que o alg do header seja exatamente o da chave,
+// impedindo ataques de troca de algoritmo (ex.: "none" ou HS256 com chave pública).
+// opts acrescenta validações do tipo de token, como a audiência.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims, opts ...jwt.ParserOption) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := ks.keys[kid]
+		if !ok {
+			return nil, fmt.Errorf("kid desconhecido: %q", kid)
+		}
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("algoritmo inesperado: %s", token.Method.Alg())
+		}
+		return key.verifyKey, nil
+	}, append([]jwt.ParserOption{jwt.WithValidMethods([]string{AlgHS256, AlgRS256, AlgEdDSA}), jwt.WithExpirationRequired()}, opts...)...)
+}
+
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS publica as chaves públicas para validação offline. Chaves HMAC nunca
+// são expostas.
+func (ks *KeySet) JWKS() JWKS {
+	set := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		switch pub := key.verifyKey.(type) {
+		case *rsa.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "RSA",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				N:         base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				E:         base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			})
+		case ed25519.PublicKey:
+			set.Keys = append(set.Keys, JWK{
+				KeyType:   "OKP",
+				KeyID:     key.ID,
+				Use:       "sig",
+				Algorithm: key.Algorithm,
+				Curve:     "Ed25519",
+				X:         base64.RawURLEncoding.EncodeToString(pub),
+			})
+		}
+	}
+	return set
+}
+
+func parseKey(kc KeyConfig) (*Key, error) {
+	key := &Key{ID: kc.KID, Algorithm: kc.Algorithm}
+
+	switch kc.Algorithm {
+	case AlgHS256:
+		if len(kc.Secret) < minSecretLength {
+			return nil, fmt.Errorf("segredo HS256 deve ter ao menos %d bytes", minSecretLength)
+		}
+		key.method = jwt.SigningMethodHS256
+		key.signKey = []byte(kc.Secret)
+		key.verifyKey = []byte(kc.Secret)
+		return key, nil
+
+	case AlgRS256:
+		key.method = jwt.SigningMethodRS256
+		if kc.PrivateKey != "" {
+			priv, err := parsePrivateKey(kc.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			rsaKey, ok := priv.(*rsa.PrivateKey)
+			if !ok {
+				return nil, errors.New("chave privada não é RSA")
+			}
+			key.signKey = rsaKey
+			key.verifyKey = &rsaKey.PublicKey
+			return key, nil
+		}
+		pub, err := parsePublicKey(kc.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		rsaPub, ok := pub.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.New("chave pública não é RSA")
+		}
+		key.verifyKey = rsaPub
+		return key, nil
+
+	case AlgEdDSA:
+		key.method = jwt.SigningMethodEdDSA
+		if kc.PrivateKey != "" {
+			priv, err := parsePrivateKey(kc.PrivateKey)
+			if err != nil {
+				return nil, err
+			}
+			edKey, ok := priv.(ed25519.PrivateKey)
+			if !ok {
+				return nil, errors.New("chave privada não é Ed25519")
+			}
+			key.signKey = edKey
+			key.verifyKey = edKey.Public()
+			return key, nil
+		}
+		pub, err := parsePublicKey(kc.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		edPub, ok := pub.(ed25519.PublicKey)
+		if !ok {
+			return nil, errors.New("chave pública não é Ed25519")
+		}
+		key.verifyKey = edPub
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("algoritmo não suportado: %q", kc.Algorithm)
+}
+
+func parsePrivateKey(data string) (crypto.Signer, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("chave privada PEM inválida")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("tipo de chave privada não suportado")
+		}
+		return signer, nil
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func parsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("chave pública PEM inválida")
+	}
+	return x509.ParsePKIXPublicKey(block.Bytes)
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Batch agrupa os vídeos enviados juntos em um upload em lote, para acompanhar
+// e baixar o conjunto. FileCount inclui os arquivos recusados, que não viram
+// vídeo.
+type Batch struct {
+	ID        string    `gorm:"type:uuid;primary_key;" json:"id"`
+	UserID    string    `gorm:"type:uuid;index;not null" json:"user_id"`
+	Name      string    `json:"name,omitempty"`
+	FileCount int       `gorm:"not null" json:"file_count"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func NewBatch(userID, name string, fileCount int) *Batch {
+	return &Batch{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		FileCount: fileCount,
+		CreatedAt: time.Now(),
+	}
+}
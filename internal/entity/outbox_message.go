@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxMessage é uma mensagem para a fila gravada na mesma transação que o
+// vídeo. O relay a publica depois e marca SentAt, garantindo entrega
+// at-least-once mesmo se o processo cair entre o commit e o envio.
+type OutboxMessage struct {
+	ID            string     `gorm:"type:uuid;primary_key;" json:"id"`
+	VideoID       string     `gorm:"type:uuid;index;not null" json:"video_id"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index:idx_outbox_pending,priority:2;not null" json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `gorm:"index:idx_outbox_pending,priority:1" json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+func (OutboxMessage) TableName() string { return "outbox" }
+
+func NewVideoOutboxMessage(message VideoMessage) *OutboxMessage {
+	payload, _ := json.Marshal(message)
+	now := time.Now()
+	return &OutboxMessage{
+		ID:            uuid.New().String(),
+		VideoID:       message.VideoID,
+		Payload:       string(payload),
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+}
+
+func (m *OutboxMessage) VideoMessage() (VideoMessage, error) {
+	var message VideoMessage
+	err := json.Unmarshal([]byte(m.Payload), &message)
+	return message, err
+}
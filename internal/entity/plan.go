@@ -0,0 +1,30 @@
+package entity
+
+import "time"
+
+// DefaultPlanID é o plano atribuído no cadastro. Os limites de cada plano
+// ficam na tabela plans e podem ser ajustados sem deploy.
+const DefaultPlanID = "free"
+
+// Plan define a cota dos usuários que o assinam. Limite zero significa
+// ilimitado.
+type Plan struct {
+	ID                string    `gorm:"primary_key" json:"id"`
+	Name              string    `gorm:"not null" json:"name"`
+	MaxStorageBytes   int64     `json:"max_storage_bytes"`
+	MaxVideosInFlight int64     `json:"max_videos_in_flight"`
+	MaxUploadsPerHour int64     `json:"max_uploads_per_hour"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// DefaultPlan é criado na inicialização quando ainda não existe no banco.
+func DefaultPlan() *Plan {
+	return &Plan{
+		ID:                DefaultPlanID,
+		Name:              "Gratuito",
+		MaxStorageBytes:   10 << 30,
+		MaxVideosInFlight: 3,
+		MaxUploadsPerHour: 20,
+	}
+}
@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Preset é um conjunto nomeado de opções de processamento do usuário. O
+// preset padrão (no máximo um por usuário) é usado nos uploads que não
+// indicam nenhum; as opções são copiadas para o vídeo, então editar ou
+// remover o preset não afeta vídeos já enviados.
+type Preset struct {
+	ID        string            `gorm:"type:uuid;primary_key;" json:"id"`
+	UserID    string            `gorm:"type:uuid;not null;uniqueIndex:idx_presets_user_name,priority:1;uniqueIndex:idx_presets_user_default,where:is_default" json:"user_id"`
+	Name      string            `gorm:"not null;uniqueIndex:idx_presets_user_name,priority:2" json:"name"`
+	Options   ProcessingOptions `gorm:"embedded;embeddedPrefix:option_" json:"options"`
+	IsDefault bool              `gorm:"not null;default:false" json:"is_default"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+func NewPreset(userID, name string, options ProcessingOptions) *Preset {
+	return &Preset{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		Name:      name,
+		Options:   options,
+		CreatedAt: time.Now(),
+	}
+}
@@ -0,0 +1,37 @@
+package entity
+
+const (
+	FrameFormatPNG  = "png"
+	FrameFormatJPEG = "jpeg"
+)
+
+// ProcessingOptions são as escolhas do usuário para a extração de frames,
+// enviadas ao worker na mensagem da fila. Campos zerados não foram
+// informados e recebem o valor padrão; MaxFrames zero é sem limite.
+type ProcessingOptions struct {
+	FrameInterval float64 `json:"frame_interval_seconds"`
+	OutputFormat  string  `json:"output_format"`
+	MaxFrames     int     `json:"max_frames"`
+}
+
+// DefaultProcessingOptions é o comportamento anterior às opções: um frame
+// por segundo em PNG.
+func DefaultProcessingOptions() ProcessingOptions {
+	return ProcessingOptions{FrameInterval: 1, OutputFormat: FrameFormatPNG}
+}
+
+// WithDefaults completa os campos zerados com os de base. MaxFrames zero
+// também herda o de base: quem precisa aplicar "sem limite" sobre um limite
+// de base o faz depois.
+func (o ProcessingOptions) WithDefaults(base ProcessingOptions) ProcessingOptions {
+	if o.FrameInterval == 0 {
+		o.FrameInterval = base.FrameInterval
+	}
+	if o.OutputFormat == "" {
+		o.OutputFormat = base.OutputFormat
+	}
+	if o.MaxFrames == 0 {
+		o.MaxFrames = base.MaxFrames
+	}
+	return o
+}
@@ -0,0 +1,91 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenTTL define por quanto tempo uma sessão sem uso continua válida.
+const RefreshTokenTTL = 30 * 24 * time.Hour
+
+// Session representa um login em um dispositivo. Apenas o hash do refresh
+// token é persistido; o token em si só é conhecido pelo cliente.
+type Session struct {
+	ID                string     `gorm:"type:uuid;primary_key;" json:"id"`
+	UserID            string     `gorm:"type:uuid;index;not null" json:"-"`
+	RefreshTokenHash  string     `gorm:"uniqueIndex;not null" json:"-"`
+	PreviousTokenHash string     `gorm:"index" json:"-"`
+	UserAgent         string     `json:"user_agent"`
+	IPAddress         string     `json:"ip_address"`
+	ExpiresAt         time.Time  `json:"expires_at"`
+	LastUsedAt        time.Time  `json:"last_used_at"`
+	RevokedAt         *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"-"`
+}
+
+func NewSession(userID, userAgent, ipAddress string) (*Session, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &Session{
+		ID:               uuid.New().String(),
+		UserID:           userID,
+		RefreshTokenHash: HashToken(token),
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		ExpiresAt:        now.Add(RefreshTokenTTL),
+		LastUsedAt:       now,
+		CreatedAt:        now,
+	}, token, nil
+}
+
+func (s *Session) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// Rotate troca o refresh token da sessão, guardando o hash anterior para
+// detectar reutilização de um token já trocado.
+func (s *Session) Rotate() (string, error) {
+	token, err := newToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	s.PreviousTokenHash = s.RefreshTokenHash
+	s.RefreshTokenHash = HashToken(token)
+	s.LastUsedAt = now
+	s.ExpiresAt = now.Add(RefreshTokenTTL)
+	return token, nil
+}
+
+func (s *Session) Revoke() {
+	if s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt = &now
+	}
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// newToken gera os tokens opacos de sessão e de compartilhamento; só o
+// HashToken deles é persistido.
+func newToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
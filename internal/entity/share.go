@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Share é um link público para baixar a saída de um vídeo sem conta. Como nas
+// sessões, só o hash do token é persistido. MaxDownloads zero não limita os
+// downloads; sem senha, PasswordHash fica vazio. PasswordFailures conta as
+// senhas incorretas seguidas e LockedUntil bloqueia novas tentativas.
+type Share struct {
+	ID               string     `gorm:"type:uuid;primary_key;" json:"id"`
+	VideoID          string     `gorm:"type:uuid;index;not null" json:"video_id"`
+	UserID           string     `gorm:"type:uuid;index;not null" json:"user_id"`
+	TokenHash        string     `gorm:"uniqueIndex;not null" json:"-"`
+	PasswordHash     string     `json:"-"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expires_at"`
+	MaxDownloads     int        `gorm:"not null;default:0" json:"max_downloads"`
+	DownloadCount    int        `gorm:"not null;default:0" json:"download_count"`
+	LastDownloadAt   *time.Time `json:"last_download_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	PasswordFailures int        `gorm:"not null;default:0" json:"-"`
+	LockedUntil      *time.Time `json:"-"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// NewShare devolve também o token, que só é conhecido nesse momento.
+func NewShare(videoID, userID string, expiresAt time.Time, maxDownloads int, password string) (*Share, string, error) {
+	token, err := newToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	share := &Share{
+		ID:           uuid.New().String(),
+		VideoID:      videoID,
+		UserID:       userID,
+		TokenHash:    HashToken(token),
+		ExpiresAt:    expiresAt,
+		MaxDownloads: maxDownloads,
+		CreatedAt:    time.Now(),
+	}
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, "", err
+		}
+		share.PasswordHash = string(hash)
+	}
+	return share, token, nil
+}
+
+func (s *Share) HasPassword() bool {
+	return s.PasswordHash != ""
+}
+
+func (s *Share) ValidatePassword(password string) bool {
+	if !s.HasPassword() {
+		return true
+	}
+	return bcrypt.CompareHashAndPassword([]byte(s.PasswordHash), []byte(password)) == nil
+}
+
+func (s *Share) IsLocked(now time.Time) bool {
+	return s.LockedUntil != nil && now.Before(*s.LockedUntil)
+}
+
+func (s *Share) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt) &&
+		(s.MaxDownloads == 0 || s.DownloadCount < s.MaxDownloads)
+}
+
+func (s *Share) Revoke() {
+	if s.RevokedAt == nil {
+		now := time.Now()
+		s.RevokedAt = &now
+	}
+}
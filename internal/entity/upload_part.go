@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// UploadPart registra cada parte já enviada de um upload multipart, permitindo
+// que o cliente retome o envio a partir da última parte confirmada.
+type UploadPart struct {
+	VideoID    string    `gorm:"type:uuid;primaryKey" json:"-"`
+	PartNumber int32     `gorm:"primaryKey;autoIncrement:false" json:"part_number"`
+	ETag       string    `gorm:"column:etag;not null" json:"etag"`
+	Size       int64     `json:"size"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+func NewUploadPart(videoID string, partNumber int32, etag string, size int64) *UploadPart {
+	return &UploadPart{
+		VideoID:    videoID,
+		PartNumber: partNumber,
+		ETag:       etag,
+		Size:       size,
+		CreatedAt:  time.Now(),
+	}
+}
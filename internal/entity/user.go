@@ -7,14 +7,22 @@ import (
 	"gorm.io/gorm"
 )
 
+// Em User, MaxUploadSize sobrescreve o tamanho máximo de vídeo padrão (zero
+// usa o da UploadPolicy) e PlanID define as cotas de armazenamento e envio.
+// EmailVerifiedAt fica nulo até o usuário abrir o link de confirmação e
+// VerificationSentAt marca o último link enviado, para limitar os reenvios.
 type User struct {
-	ID        string         `gorm:"type:uuid;primary_key;" json:"id"`
-	Username  string         `gorm:"uniqueIndex;not null" json:"username"`
-	Email     string         `gorm:"uniqueIndex;not null" json:"email"`
-	Password  string         `gorm:"not null" json:"-"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                 string         `gorm:"type:uuid;primary_key;" json:"id"`
+	Username           string         `gorm:"uniqueIndex;not null" json:"username"`
+	Email              string         `gorm:"uniqueIndex;not null" json:"email"`
+	EmailVerifiedAt    *time.Time     `json:"email_verified_at,omitempty"`
+	VerificationSentAt *time.Time     `json:"-"`
+	Password           string         `gorm:"not null" json:"-"`
+	MaxUploadSize      int64          `json:"-"`
+	PlanID             string         `gorm:"not null;default:'free'" json:"plan_id"`
+	CreatedAt          time.Time      `json:"created_at"`
+	UpdatedAt          time.Time      `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func NewUser(username, email, password string) (*User, error) {
@@ -22,15 +30,29 @@ func NewUser(username, email, password string) (*User, error) {
 	if err != nil {
 		return nil, err
 	}
+	now := time.Now()
 	return &User{
-		ID:       uuid.New().String(),
-		Username: username,
-		Email:    email,
-		Password: string(hash),
+		ID:                 uuid.New().String(),
+		Username:           username,
+		Email:              email,
+		Password:           string(hash),
+		PlanID:             DefaultPlanID,
+		VerificationSentAt: &now,
 	}, nil
 }
 
 func (u *User) ValidatePassword(password string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
 	return err == nil
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) VerifyEmail() {
+	if u.EmailVerifiedAt == nil {
+		now := time.Now()
+		u.EmailVerifiedAt = &now
+	}
 }
\ No newline at end of file
@@ -1,6 +1,9 @@
 package entity
 
 import (
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -10,34 +13,165 @@ import (
 type VideoStatus string
 
 const (
+	StatusUploading  VideoStatus = "UPLOADING"
 	StatusPending    VideoStatus = "PENDING"
 	StatusProcessing VideoStatus = "PROCESSING"
 	StatusDone       VideoStatus = "DONE"
 	StatusError      VideoStatus = "ERROR"
+	StatusCanceled   VideoStatus = "CANCELED"
 )
 
+var ErrInvalidTransition = errors.New("transição de status inválida")
+
+// videoTransitions é a máquina de estados do vídeo: qualquer mudança fora
+// deste mapa (ex.: DONE -> PROCESSING) é rejeitada. CANCELED só é possível
+// antes do worker assumir o vídeo, e o worker não consegue sair dele.
+// ERROR -> PENDING é o reprocessamento pedido pelo usuário.
+var videoTransitions = map[VideoStatus][]VideoStatus{
+	StatusUploading:  {StatusPending, StatusError, StatusCanceled},
+	StatusPending:    {StatusProcessing, StatusError, StatusCanceled},
+	StatusProcessing: {StatusDone, StatusError},
+	StatusError:      {StatusPending},
+}
+
+// workerTransitions é o subconjunto de videoTransitions que o worker pode
+// pedir. As demais transições são do usuário (cancelamento, reprocessamento)
+// ou das rotinas da API (confirmação de upload, vídeos parados), que também
+// gravam a mensagem na outbox quando devolvem o vídeo à fila.
+var workerTransitions = map[VideoStatus][]VideoStatus{
+	StatusPending:    {StatusProcessing, StatusError},
+	StatusProcessing: {StatusDone, StatusError},
+}
+
+// InFlightStatuses são os status de vídeos que ainda ocupam o pipeline,
+// contados na cota de vídeos simultâneos.
+var InFlightStatuses = []VideoStatus{StatusUploading, StatusPending, StatusProcessing}
+
+func (s VideoStatus) Valid() bool {
+	switch s {
+	case StatusUploading, StatusPending, StatusProcessing, StatusDone, StatusError, StatusCanceled:
+		return true
+	}
+	return false
+}
+
+func (s VideoStatus) CanTransitionTo(to VideoStatus) bool {
+	return slices.Contains(videoTransitions[s], to)
+}
+
+func (s VideoStatus) WorkerCanTransitionTo(to VideoStatus) bool {
+	return slices.Contains(workerTransitions[s], to) && s.CanTransitionTo(to)
+}
+
 type Video struct {
 	ID           string         `gorm:"type:uuid;primary_key;" json:"id"`
-	UserID       string         `gorm:"type:uuid;index;not null" json:"user_id"`
+	UserID       string         `gorm:"type:uuid;index;index:idx_videos_user_created,priority:1;not null" json:"user_id"`
+	BatchID      *string        `gorm:"type:uuid;index" json:"batch_id,omitempty"`
 	FileName     string         `json:"file_name"`
 	InputBucket  string         `json:"input_bucket"`
 	InputKey     string         `json:"input_key"`
+	InputSize    int64          `json:"input_size"`
+	ContentType  string         `json:"content_type"`
 	OutputBucket string         `json:"output_bucket"`
 	OutputKey    string         `json:"output_key"`
+	OutputSize   int64          `json:"output_size"`
+	FrameCount   int            `json:"frame_count"`
+	UploadID     string         `json:"-"`
 	Status       VideoStatus    `gorm:"index;default:'PENDING'" json:"status"`
 	ErrorMessage string         `json:"error_message,omitempty"`
-	CreatedAt    time.Time      `json:"created_at"`
+	Version      int            `gorm:"not null;default:1" json:"version"`
+
+	// Metadados lidos do contêiner no upload; ficam zerados quando não foi
+	// possível extraí-los (ex.: AVI).
+	DurationSeconds float64 `json:"duration_seconds"`
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	VideoCodec      string  `json:"video_codec"`
+	Bitrate         int64   `json:"bitrate"`
+
+	// Opções de extração pedidas no upload (e o preset de onde vieram) e o
+	// identificador que acompanha a mensagem da fila e os logs do worker; um
+	// reprocessamento gera outro.
+	Options       ProcessingOptions `gorm:"embedded;embeddedPrefix:option_" json:"options"`
+	PresetID      string            `json:"preset_id,omitempty"`
+	CorrelationID string            `json:"correlation_id"`
+
+	// Reenvios automáticos à fila de um vídeo parado em PENDING ou
+	// PROCESSING desde o último sinal do worker; NextRequeueAt é o backoff até
+	// o próximo. ActivityAt é a entrada no status atual ou o último heartbeat
+	// do worker, e os reenvios não o alteram.
+	RequeueAttempts int        `gorm:"not null;default:0" json:"requeue_attempts"`
+	NextRequeueAt   *time.Time `json:"-"`
+	ActivityAt      *time.Time `json:"-"`
+
+	// Reprocessamentos pedidos pelo usuário depois de um ERROR.
+	RetryAttempts int `gorm:"not null;default:0" json:"retry_attempts"`
+
+	ProcessingStartedAt  *time.Time `json:"processing_started_at,omitempty"`
+	ProcessingFinishedAt *time.Time `json:"processing_finished_at,omitempty"`
+
+	CreatedAt    time.Time      `gorm:"index:idx_videos_user_created,priority:2" json:"created_at"`
 	UpdatedAt    time.Time      `json:"updated_at"`
 	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 func NewVideo(userID, fileName, inputKey string) *Video {
+	now := time.Now()
 	return &Video{
-		ID:        uuid.New().String(),
-		UserID:    userID,
-		FileName:  fileName,
-		InputKey:  inputKey,
-		Status:    StatusPending,
-		CreatedAt: time.Now(),
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		FileName:      fileName,
+		InputKey:      inputKey,
+		Status:        StatusPending,
+		Version:       1,
+		CorrelationID: uuid.New().String(),
+		ActivityAt:    &now,
+		CreatedAt:     now,
+	}
+}
+
+// TransitionTo muda o status respeitando a máquina de estados e devolve o
+// evento que deve ser gravado no histórico junto com o vídeo.
+func (v *Video) TransitionTo(to VideoStatus, actor, reason string) (*VideoStatusEvent, error) {
+	if !v.Status.CanTransitionTo(to) {
+		return nil, fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, v.Status, to)
+	}
+
+	event := NewVideoStatusEvent(v.ID, v.Status, to, actor, reason)
+	v.Status = to
+	v.ActivityAt = &event.CreatedAt
+
+	switch to {
+	case StatusProcessing:
+		v.ProcessingStartedAt = &event.CreatedAt
+		v.ProcessingFinishedAt = nil
+	case StatusDone, StatusError:
+		if v.ProcessingStartedAt != nil {
+			v.ProcessingFinishedAt = &event.CreatedAt
+		}
+		v.NextRequeueAt = nil
+	}
+
+	return event, nil
+}
+
+// Heartbeat registra um sinal de vida do worker: o vídeo deixa de contar como
+// parado. Os reenvios já feitos continuam contando, para que um vídeo que
+// derruba todo worker que o assume chegue a ERROR em vez de voltar à fila
+// para sempre; um vídeo que o worker mantém vivo nunca é reenviado.
+func (v *Video) Heartbeat(at time.Time) {
+	v.ActivityAt = &at
+}
+
+// DownloadAvailable indica se já existe um ZIP de frames para baixar.
+func (v *Video) DownloadAvailable() bool {
+	return v.Status == StatusDone && v.OutputKey != ""
+}
+
+// ProcessingDuration só é conhecida depois que o worker termina.
+func (v *Video) ProcessingDuration() (time.Duration, bool) {
+	if v.ProcessingStartedAt == nil || v.ProcessingFinishedAt == nil {
+		return 0, false
 	}
+	return v.ProcessingFinishedAt.Sub(*v.ProcessingStartedAt), true
 }
\ No newline at end of file
@@ -0,0 +1,65 @@
+package entity
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VideoMessageSchemaVersion é a versão atual do envelope. A versão 1 só tinha
+// video_id e email; os dois continuam no mesmo lugar para que workers antigos
+// sigam funcionando.
+const VideoMessageSchemaVersion = 2
+
+// VideoMessage é o corpo da mensagem que manda o worker processar um vídeo.
+// Leva tudo o que o worker precisa para não consultar o banco da API. O
+// formato é o mesmo em todos os backends de fila (SQS, Postgres, memória).
+type VideoMessage struct {
+	SchemaVersion int               `json:"schema_version"`
+	MessageID     string            `json:"message_id"`
+	CorrelationID string            `json:"correlation_id"`
+	VideoID       string            `json:"video_id"`
+	UserID        string            `json:"user_id"`
+	Email         string            `json:"email"`
+	FileName      string            `json:"file_name"`
+	InputBucket   string            `json:"input_bucket"`
+	InputKey      string            `json:"input_key"`
+	Options       ProcessingOptions `json:"options"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
+
+// NewVideoMessage monta a mensagem a partir do estado atual do vídeo. Vídeos
+// gravados antes das opções de processamento recebem as opções padrão.
+func NewVideoMessage(video *Video, email string) VideoMessage {
+	correlationID := video.CorrelationID
+	if correlationID == "" {
+		correlationID = video.ID
+	}
+
+	return VideoMessage{
+		SchemaVersion: VideoMessageSchemaVersion,
+		MessageID:     uuid.New().String(),
+		CorrelationID: correlationID,
+		VideoID:       video.ID,
+		UserID:        video.UserID,
+		Email:         email,
+		FileName:      video.FileName,
+		InputBucket:   video.InputBucket,
+		InputKey:      video.InputKey,
+		Options:       video.Options.WithDefaults(DefaultProcessingOptions()),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// Attributes são enviados junto do corpo (message attributes no SQS) para
+// rotear e filtrar sem decodificar o JSON.
+func (m VideoMessage) Attributes() map[string]string {
+	return map[string]string{
+		"schema_version": strconv.Itoa(m.SchemaVersion),
+		"message_id":     m.MessageID,
+		"correlation_id": m.CorrelationID,
+		"video_id":       m.VideoID,
+		"output_format":  m.Options.OutputFormat,
+	}
+}
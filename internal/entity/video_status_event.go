@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Atores registrados no histórico de status.
+const ActorSystem = "system"
+
+func UserActor(userID string) string {
+	return "user:" + userID
+}
+
+func ServiceActor(name string) string {
+	return "service:" + name
+}
+
+type VideoStatusEvent struct {
+	ID         string      `gorm:"type:uuid;primary_key;" json:"id"`
+	VideoID    string      `gorm:"type:uuid;index;not null" json:"video_id"`
+	FromStatus VideoStatus `json:"from_status"`
+	ToStatus   VideoStatus `gorm:"not null" json:"to_status"`
+	Actor      string      `json:"actor"`
+	Reason     string      `json:"reason,omitempty"`
+	CreatedAt  time.Time   `gorm:"index" json:"created_at"`
+}
+
+func NewVideoStatusEvent(videoID string, from, to VideoStatus, actor, reason string) *VideoStatusEvent {
+	return &VideoStatusEvent{
+		ID:         uuid.New().String(),
+		VideoID:    videoID,
+		FromStatus: from,
+		ToStatus:   to,
+		Actor:      actor,
+		Reason:     reason,
+		CreatedAt:  time.Now(),
+	}
+}
+
+// VideoStatusChange é a notificação enviada em tempo real aos clientes
+// quando um vídeo muda de status.
+type VideoStatusChange struct {
+	VideoID      string      `json:"video_id"`
+	UserID       string      `json:"user_id"`
+	FileName     string      `json:"file_name"`
+	FromStatus   VideoStatus `json:"from_status"`
+	Status       VideoStatus `json:"status"`
+	ErrorMessage string      `json:"error_message,omitempty"`
+	OccurredAt   time.Time   `json:"occurred_at"`
+}
+
+func NewVideoStatusChange(video *Video, event *VideoStatusEvent) VideoStatusChange {
+	return VideoStatusChange{
+		VideoID:      video.ID,
+		UserID:       video.UserID,
+		FileName:     video.FileName,
+		FromStatus:   event.FromStatus,
+		Status:       event.ToStatus,
+		ErrorMessage: video.ErrorMessage,
+		OccurredAt:   event.CreatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"hackaton-service-api/internal/usecase"
 	"net/http"
 
@@ -26,8 +27,29 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+type TokenResponse struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Username     string `json:"username"`
+}
+
+func newTokenResponse(tokens *usecase.AuthTokens) TokenResponse {
+	return TokenResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		Username:     tokens.Username,
+	}
+}
+
 // Register godoc
 // @Summary Registra um novo usuário
+// @Description Envia um link de confirmação para o e-mail informado; o envio de vídeos fica bloqueado até a confirmação. Se o e-mail não puder ser enviado, a conta é criada mesmo assim e o link pode ser reenviado depois do login.
 // @Tags Auth
 // @Accept json
 // @Produce json
@@ -41,7 +63,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	if err := h.UserUC.Register(req.Username, req.Email, req.Password); err != nil {
+	err := h.UserUC.Register(req.Username, req.Email, req.Password)
+	if errors.Is(err, usecase.ErrVerificationNotSent) {
+		c.JSON(http.StatusCreated, gin.H{
+			"message": "Usuário criado com sucesso",
+			"warning": "Não foi possível enviar o e-mail de confirmação; faça login e use /api/verify-email/resend",
+		})
+		return
+	}
+	if err != nil {
 		status := http.StatusInternalServerError
 		if err.Error() == "usuário já existe" || err.Error() == "email já cadastrado" {
 			status = http.StatusConflict
@@ -55,12 +85,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary Realiza login do usuário
-// @Description Autentica o usuário e retorna um token JWT
+// @Description Autentica o usuário e retorna um token JWT de curta duração e um refresh token para renová-lo
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Credenciais de Login"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} TokenResponse
 // @Router /api/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -69,14 +99,153 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	token, username, err := h.UserUC.Login(req.Username, req.Password)
+	tokens, err := h.UserUC.Login(req.Username, req.Password, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"token":    token,
-		"username": username,
-	})
-}
\ No newline at end of file
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// Refresh godoc
+// @Summary Renova o token de acesso
+// @Description Troca o refresh token por um novo par de tokens. O refresh token anterior deixa de valer; reutilizá-lo encerra a sessão.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshRequest true "Refresh token"
+// @Success 200 {object} TokenResponse
+// @Failure 401 {object} map[string]string "Sessão inválida ou expirada"
+// @Router /api/token/refresh [post]
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	var req RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos"})
+		return
+	}
+
+	tokens, err := h.UserUC.Refresh(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newTokenResponse(tokens))
+}
+
+// Logout godoc
+// @Summary Encerra a sessão atual
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Router /api/logout [post]
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID := c.GetString("userID")
+	sessionID := c.GetString("sessionID")
+
+	if err := h.UserUC.Logout(userID, sessionID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Sessão encerrada"})
+}
+
+// LogoutAll godoc
+// @Summary Encerra todas as sessões do usuário
+// @Description Revoga os refresh tokens de todos os dispositivos; os tokens de acesso emitidos deixam de ser aceitos imediatamente.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Router /api/logout/all [post]
+func (h *AuthHandler) LogoutAll(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	if err := h.UserUC.LogoutAll(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Todas as sessões foram encerradas"})
+}
+
+// ListSessions godoc
+// @Summary Lista as sessões ativas do usuário
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.Session
+// @Router /api/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	sessions, err := h.UserUC.ListSessions(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// VerifyEmail godoc
+// @Summary Confirma o e-mail do usuário
+// @Description Rota pública aberta pelo link enviado por e-mail. O link vale por 48 horas e só para o e-mail para o qual foi enviado. Confirmar de novo não é erro.
+// @Tags Auth
+// @Produce json
+// @Param token query string true "Token de confirmação"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string "Link inválido ou expirado"
+// @Router /api/verify-email [get]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Token obrigatório"})
+		return
+	}
+
+	user, err := h.UserUC.VerifyEmail(token)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, usecase.ErrInvalidVerificationToken) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "E-mail confirmado", "email": user.Email})
+}
+
+// ResendVerification godoc
+// @Summary Reenvia o e-mail de confirmação
+// @Description Os links enviados antes continuam valendo até expirar. Um novo envio só é aceito depois de um intervalo mínimo desde o anterior.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} map[string]string
+// @Failure 409 {object} map[string]string "E-mail já confirmado"
+// @Failure 429 {object} map[string]string "E-mail de confirmação enviado há pouco tempo"
+// @Failure 502 {object} map[string]string "Falha no envio do e-mail"
+// @Router /api/verify-email/resend [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	err := h.UserUC.ResendVerification(c.GetString("userID"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, usecase.ErrEmailAlreadyVerified):
+			status = http.StatusConflict
+		case errors.Is(err, usecase.ErrVerificationCooldown):
+			status = http.StatusTooManyRequests
+		case errors.Is(err, usecase.ErrVerificationNotSent):
+			status = http.StatusBadGateway
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "E-mail de confirmação enviado"})
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type BatchHandler struct {
+	BatchUC *usecase.BatchUseCase
+}
+
+func NewBatchHandler(batchUC *usecase.BatchUseCase) *BatchHandler {
+	return &BatchHandler{BatchUC: batchUC}
+}
+
+// BatchItemResponse é o resultado de um arquivo do lote. Code traz o status
+// HTTP que o arquivo teria recebido no upload individual.
+type BatchItemResponse struct {
+	FileName string `json:"file_name"`
+	VideoID  string `json:"video_id,omitempty"`
+	Status   string `json:"status,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Code     int    `json:"code"`
+}
+
+// UploadBatch godoc
+// @Summary Realiza o upload de vários vídeos
+// @Description Envia até 20 arquivos no campo videos, com as mesmas validações e opções de processamento do upload individual, aplicadas a todos. A recusa de um arquivo não impede os demais: cada item da resposta traz o vídeo criado ou o erro e o status HTTP correspondente. As cotas do plano valem para o lote inteiro: os arquivos além do limite de vídeos em andamento, de envios por hora ou de espaço são recusados sem envio, e o lote todo é recusado quando nenhum cabe.
+// @Tags Batches
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param videos formData file true "Arquivos de vídeo (repita o campo para cada arquivo)"
+// @Param name formData string false "Nome do lote"
+// @Param preset_id formData string false "ID de um preset do usuário"
+// @Param frame_interval formData number false "Segundos entre frames extraídos (0.1 a 3600)"
+// @Param output_format formData string false "Formato dos frames: png ou jpeg"
+// @Param max_frames formData int false "Máximo de frames extraídos (0 = sem limite, até 10000)"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Nenhum arquivo, arquivos demais ou opções inválidas"
+// @Failure 403 {object} map[string]string "E-mail não confirmado ou cota de armazenamento do plano excedida"
+// @Failure 404 {object} map[string]string "Preset não encontrado"
+// @Failure 429 {object} map[string]string "Limite de vídeos em andamento ou de envios por hora atingido"
+// @Router /api/upload/batch [post]
+func (h *BatchHandler) UploadBatch(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	multipartForm, err := c.MultipartForm()
+	if err != nil || len(multipartForm.File["videos"]) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivos obrigatórios"})
+		return
+	}
+
+	var form ProcessingOptionsForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Opções de processamento inválidas: " + err.Error()})
+		return
+	}
+
+	headers := multipartForm.File["videos"]
+	files := make([]usecase.BatchFile, len(headers))
+	for i, header := range headers {
+		files[i] = usecase.BatchFile{
+			FileName: header.Filename,
+			Size:     header.Size,
+			Open:     func() (multipart.File, error) { return header.Open() },
+		}
+	}
+
+	batch, results, err := h.BatchUC.Upload(userID, c.PostForm("name"), files, form.toRequest())
+	if err != nil {
+		c.JSON(batchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	items := make([]BatchItemResponse, len(results))
+	accepted := 0
+	for i, result := range results {
+		items[i] = BatchItemResponse{FileName: result.FileName, Code: http.StatusAccepted}
+		if result.Err != nil {
+			items[i].Error = result.Err.Error()
+			items[i].Code = uploadErrorStatus(result.Err)
+			continue
+		}
+		items[i].VideoID = result.Video.ID
+		items[i].Status = string(result.Video.Status)
+		accepted++
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"batch_id": batch.ID,
+		"accepted": accepted,
+		"rejected": len(items) - accepted,
+		"files":    items,
+	})
+}
+
+// ListBatches godoc
+// @Summary Lista os lotes recentes do usuário
+// @Tags Batches
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.Batch
+// @Router /api/batches [get]
+func (h *BatchHandler) ListBatches(c *gin.Context) {
+	batches, err := h.BatchUC.List(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, batches)
+}
+
+// GetBatch godoc
+// @Summary Progresso de um lote
+// @Description Traz os vídeos do lote, a contagem por status e finished, verdadeiro quando nenhum vídeo ainda está em andamento.
+// @Tags Batches
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do lote"
+// @Success 200 {object} usecase.BatchDetail
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Lote não encontrado"
+// @Router /api/batches/{id} [get]
+func (h *BatchHandler) GetBatch(c *gin.Context) {
+	detail, err := h.BatchUC.Get(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(batchErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
+
+func batchErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidBatch), errors.Is(err, usecase.ErrInvalidOptions):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrAccessDenied), errors.Is(err, usecase.ErrEmailNotVerified), errors.Is(err, usecase.ErrQuotaExceeded):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
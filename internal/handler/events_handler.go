@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"hackaton-service-api/internal/entity"
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Intervalo dos comentários de keep-alive, abaixo do timeout ocioso típico de
+// load balancers (60s).
+const sseHeartbeatInterval = 25 * time.Second
+
+type StatusSubscriber interface {
+	Subscribe(userID string) (<-chan entity.VideoStatusChange, func())
+}
+
+type EventsHandler struct {
+	Subscriber StatusSubscriber
+}
+
+func NewEventsHandler(subscriber StatusSubscriber) *EventsHandler {
+	return &EventsHandler{Subscriber: subscriber}
+}
+
+// StreamStatus godoc
+// @Summary Stream de mudanças de status dos vídeos
+// @Description Mantém uma conexão Server-Sent Events e envia um evento "status" a cada transição de um vídeo do usuário. Eventos emitidos enquanto o cliente estava desconectado não são reenviados; ao reconectar, recarregue a lista.
+// @Tags Videos
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Success 200 {object} entity.VideoStatusChange "event: status"
+// @Router /api/videos/events [get]
+func (h *EventsHandler) StreamStatus(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	changes, unsubscribe := h.Subscriber.Subscribe(userID)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.SSEvent("ready", gin.H{"user_id": userID})
+	c.Writer.Flush()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case change, ok := <-changes:
+			if !ok {
+				return false
+			}
+			c.SSEvent("status", change)
+			return true
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": ping\n\n")
+			return err == nil
+		}
+	})
+}
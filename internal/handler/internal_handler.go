@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalHandler expõe as rotas consumidas pelo worker, autenticadas por
+// token de serviço ou assinatura HMAC em vez do JWT de usuário.
+type InternalHandler struct {
+	VideoUC *usecase.VideoUseCase
+}
+
+func NewInternalHandler(videoUC *usecase.VideoUseCase) *InternalHandler {
+	return &InternalHandler{VideoUC: videoUC}
+}
+
+type StatusUpdateRequest struct {
+	Status       entity.VideoStatus `json:"status" binding:"required"`
+	OutputBucket string             `json:"output_bucket"`
+	OutputKey    string             `json:"output_key"`
+	OutputSize   int64              `json:"output_size" binding:"gte=0"`
+	FrameCount   int                `json:"frame_count" binding:"gte=0"`
+	ErrorMessage string             `json:"error_message"`
+}
+
+// GetVideo godoc
+// @Summary Consulta um vídeo para processamento
+// @Description Rota interna do worker. Autenticação por token de serviço (Authorization: Bearer) ou por X-Timestamp + X-Signature (HMAC-SHA256).
+// @Tags Internal
+// @Produce json
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {object} entity.Video
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Router /internal/videos/{id} [get]
+func (h *InternalHandler) GetVideo(c *gin.Context) {
+	video, err := h.VideoUC.GetForProcessing(c.Param("id"))
+	if err != nil {
+		c.JSON(internalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, video)
+}
+
+// UpdateStatus godoc
+// @Summary Atualiza o status de processamento de um vídeo
+// @Description Rota interna do worker. Transições permitidas: PENDING -> PROCESSING | ERROR, PROCESSING -> DONE | ERROR. PROCESSING enviado para um vídeo já em PROCESSING é um heartbeat: evita que o vídeo seja considerado parado e reenviado à fila. DONE exige output_key (output_size e frame_count são opcionais) e ERROR exige error_message.
+// @Tags Internal
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do Vídeo"
+// @Param request body StatusUpdateRequest true "Novo status"
+// @Success 200 {object} entity.Video
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Failure 409 {object} map[string]string "Transição de status inválida ou atualização concorrente"
+// @Router /internal/videos/{id}/status [put]
+func (h *InternalHandler) UpdateStatus(c *gin.Context) {
+	var req StatusUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	video, err := h.VideoUC.UpdateProcessingStatus(c.Param("id"), usecase.StatusUpdate{
+		Actor:        entity.ServiceActor(c.GetString("serviceName")),
+		Status:       req.Status,
+		OutputBucket: req.OutputBucket,
+		OutputKey:    req.OutputKey,
+		OutputSize:   req.OutputSize,
+		FrameCount:   req.FrameCount,
+		ErrorMessage: req.ErrorMessage,
+	})
+	if err != nil {
+		c.JSON(internalErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, video)
+}
+
+func internalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrInvalidTransition), errors.Is(err, repository.ErrConcurrentUpdate):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
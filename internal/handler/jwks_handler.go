@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"hackaton-service-api/internal/auth"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JWKSProvider interface {
+	JWKS() auth.JWKS
+}
+
+type JWKSHandler struct {
+	Keys JWKSProvider
+}
+
+func NewJWKSHandler(keys JWKSProvider) *JWKSHandler {
+	return &JWKSHandler{Keys: keys}
+}
+
+// GetJWKS godoc
+// @Summary Chaves públicas de assinatura dos tokens
+// @Description Publica o JWKS para que o worker e outros serviços validem os tokens offline. Chaves HMAC não são publicadas.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} auth.JWKS
+// @Router /.well-known/jwks.json [get]
+func (h *JWKSHandler) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Keys.JWKS())
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PresetHandler struct {
+	PresetUC *usecase.PresetUseCase
+}
+
+func NewPresetHandler(presetUC *usecase.PresetUseCase) *PresetHandler {
+	return &PresetHandler{PresetUC: presetUC}
+}
+
+type PresetRequest struct {
+	Name      string                   `json:"name" binding:"required"`
+	Options   entity.ProcessingOptions `json:"options"`
+	IsDefault bool                     `json:"is_default"`
+}
+
+func (r PresetRequest) toInput() usecase.PresetInput {
+	return usecase.PresetInput{Name: r.Name, Options: r.Options, IsDefault: r.IsDefault}
+}
+
+// CreatePreset godoc
+// @Summary Cria um preset de processamento
+// @Description Grava um conjunto nomeado de opções de extração. Opções omitidas recebem o padrão do sistema. Com is_default, o preset passa a ser usado nos uploads sem preset_id e deixa de haver outro padrão.
+// @Tags Presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body PresetRequest true "Nome e opções"
+// @Success 201 {object} entity.Preset
+// @Failure 400 {object} map[string]string "Opções inválidas"
+// @Failure 409 {object} map[string]string "Nome já usado"
+// @Router /api/presets [post]
+func (h *PresetHandler) CreatePreset(c *gin.Context) {
+	var req PresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	preset, err := h.PresetUC.Create(c.GetString("userID"), req.toInput())
+	if err != nil {
+		c.JSON(presetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, preset)
+}
+
+// ListPresets godoc
+// @Summary Lista os presets do usuário
+// @Tags Presets
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} entity.Preset
+// @Router /api/presets [get]
+func (h *PresetHandler) ListPresets(c *gin.Context) {
+	presets, err := h.PresetUC.List(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, presets)
+}
+
+// GetPreset godoc
+// @Summary Detalhe de um preset
+// @Tags Presets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do preset"
+// @Success 200 {object} entity.Preset
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Preset não encontrado"
+// @Router /api/presets/{id} [get]
+func (h *PresetHandler) GetPreset(c *gin.Context) {
+	preset, err := h.PresetUC.Get(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(presetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// UpdatePreset godoc
+// @Summary Atualiza um preset
+// @Description Substitui nome, opções e is_default. Vídeos já enviados mantêm as opções com que foram enfileirados.
+// @Tags Presets
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do preset"
+// @Param request body PresetRequest true "Nome e opções"
+// @Success 200 {object} entity.Preset
+// @Failure 400 {object} map[string]string "Opções inválidas"
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Preset não encontrado"
+// @Failure 409 {object} map[string]string "Nome já usado"
+// @Router /api/presets/{id} [put]
+func (h *PresetHandler) UpdatePreset(c *gin.Context) {
+	var req PresetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	preset, err := h.PresetUC.Update(c.GetString("userID"), c.Param("id"), req.toInput())
+	if err != nil {
+		c.JSON(presetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, preset)
+}
+
+// DeletePreset godoc
+// @Summary Remove um preset
+// @Tags Presets
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do preset"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Preset não encontrado"
+// @Router /api/presets/{id} [delete]
+func (h *PresetHandler) DeletePreset(c *gin.Context) {
+	if err := h.PresetUC.Delete(c.GetString("userID"), c.Param("id")); err != nil {
+		c.JSON(presetErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Preset removido"})
+}
+
+func presetErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidOptions):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrPresetNameTaken):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
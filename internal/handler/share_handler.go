@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SharePathPrefix é a rota pública que resolve os links de compartilhamento.
+const SharePathPrefix = "/s/"
+
+type ShareHandler struct {
+	ShareUC *usecase.ShareUseCase
+	// BaseURL é o endereço público da API, usado para montar os links.
+	BaseURL string
+}
+
+func NewShareHandler(shareUC *usecase.ShareUseCase, baseURL string) *ShareHandler {
+	return &ShareHandler{ShareUC: shareUC, BaseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+type CreateShareRequest struct {
+	ExpiresInHours int    `json:"expires_in_hours"`
+	MaxDownloads   int    `json:"max_downloads"`
+	Password       string `json:"password"`
+}
+
+// ShareResponse é o link sem o token, exceto na criação (URL).
+type ShareResponse struct {
+	entity.Share
+	HasPassword bool   `json:"has_password"`
+	Active      bool   `json:"active"`
+	URL         string `json:"url,omitempty"`
+}
+
+func newShareResponse(share *entity.Share) ShareResponse {
+	return ShareResponse{Share: *share, HasPassword: share.HasPassword(), Active: share.IsActive(time.Now())}
+}
+
+// CreateShare godoc
+// @Summary Cria um link público de download
+// @Description Gera um link /s/{token} para baixar o ZIP de frames sem conta. O token aparece só nesta resposta. Validade padrão de 7 dias (máximo de 30), max_downloads 0 sem limite e senha opcional. O vídeo deve estar com status 'DONE'.
+// @Tags Shares
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Param request body CreateShareRequest false "Validade, limite de downloads e senha"
+// @Success 201 {object} ShareResponse
+// @Failure 400 {object} map[string]string "Opções inválidas"
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Failure 409 {object} map[string]string "Vídeo não está pronto"
+// @Router /api/videos/{id}/shares [post]
+func (h *ShareHandler) CreateShare(c *gin.Context) {
+	var req CreateShareRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+			return
+		}
+	}
+
+	share, token, err := h.ShareUC.Create(c.GetString("userID"), c.Param("id"), usecase.ShareInput{
+		ExpiresIn:    time.Duration(req.ExpiresInHours) * time.Hour,
+		MaxDownloads: req.MaxDownloads,
+		Password:     req.Password,
+	})
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := newShareResponse(share)
+	response.URL = h.BaseURL + SharePathPrefix + token
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListShares godoc
+// @Summary Lista os links de um vídeo
+// @Description Inclui os revogados e expirados, com a contagem de downloads de cada um.
+// @Tags Shares
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {array} ShareResponse
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Router /api/videos/{id}/shares [get]
+func (h *ShareHandler) ListShares(c *gin.Context) {
+	shares, err := h.ShareUC.List(c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	response := make([]ShareResponse, len(shares))
+	for i := range shares {
+		response[i] = newShareResponse(&shares[i])
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeShare godoc
+// @Summary Revoga um link
+// @Description O link deixa de funcionar imediatamente; links pré-assinados já entregues valem até expirar.
+// @Tags Shares
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Param share_id path string true "ID do link"
+// @Success 200 {object} ShareResponse
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo ou link não encontrado"
+// @Router /api/videos/{id}/shares/{share_id} [delete]
+func (h *ShareHandler) RevokeShare(c *gin.Context) {
+	share, err := h.ShareUC.Revoke(c.GetString("userID"), c.Param("id"), c.Param("share_id"))
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newShareResponse(share))
+}
+
+// OpenShare godoc
+// @Summary Abre um link público de download
+// @Description Rota pública, sem JWT. Redireciona para o link pré-assinado do ZIP e conta um download. Links com senha aceitam o cabeçalho X-Share-Password ou, por POST, o campo password de um formulário.
+// @Tags Shares
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token path string true "Token do link"
+// @Param X-Share-Password header string false "Senha do link"
+// @Param password formData string false "Senha do link (POST)"
+// @Success 302 "Redirecionamento para o link pré-assinado"
+// @Failure 401 {object} map[string]string "Senha ausente ou incorreta"
+// @Failure 404 {object} map[string]string "Link não encontrado"
+// @Failure 410 {object} map[string]string "Link revogado, expirado ou com downloads esgotados"
+// @Failure 429 {object} map[string]string "Link bloqueado por senhas incorretas"
+// @Router /s/{token} [get]
+// @Router /s/{token} [post]
+func (h *ShareHandler) OpenShare(c *gin.Context) {
+	password := c.GetHeader("X-Share-Password")
+	if c.Request.Method == http.MethodPost {
+		password = c.PostForm("password")
+	}
+
+	url, err := h.ShareUC.Resolve(c.Param("token"), password)
+	if err != nil {
+		c.JSON(shareErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	status := http.StatusFound
+	if c.Request.Method == http.MethodPost {
+		status = http.StatusSeeOther
+	}
+	c.Redirect(status, url)
+}
+
+func shareErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidShare):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrSharePassword):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrVideoNotReady):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrShareUnavailable):
+		return http.StatusGone
+	case errors.Is(err, usecase.ErrShareLocked):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
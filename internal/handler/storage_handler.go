@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"errors"
+	"hackaton-service-api/internal/infra/storage"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SignedObjectStore interface {
+	OpenSigned(key string, query url.Values) (storage.Object, error)
+	PutSigned(key string, query url.Values, contentType string, size int64, body io.Reader) error
+}
+
+// StorageHandler serve os links assinados dos backends local e em memória,
+// fazendo o papel das URLs pré-assinadas do S3. A autorização vem só da
+// assinatura do link, sem JWT.
+type StorageHandler struct {
+	Store SignedObjectStore
+}
+
+func NewStorageHandler(store SignedObjectStore) *StorageHandler {
+	return &StorageHandler{Store: store}
+}
+
+// Download godoc
+// @Summary Baixa um objeto por link assinado
+// @Description Usado apenas com STORAGE_BACKEND local ou memory. O link é gerado pela API (ex.: /api/videos/{id}/download) e expira conforme PRESIGN_EXPIRY (padrão de 15 minutos). Suporta Range e repete o response-content-disposition do link no Content-Disposition.
+// @Tags Storage
+// @Produce octet-stream
+// @Param key path string true "Chave do objeto"
+// @Param expires query int true "Validade (unix)"
+// @Param signature query string true "Assinatura HMAC"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string "Link inválido ou expirado"
+// @Failure 404 {object} map[string]string "Objeto não encontrado"
+// @Router /storage/{key} [get]
+func (h *StorageHandler) Download(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	obj, err := h.Store.OpenSigned(key, c.Request.URL.Query())
+	if err != nil {
+		c.JSON(storageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	defer obj.Close()
+
+	if disposition := c.Query(storage.DispositionParam); disposition != "" {
+		c.Header("Content-Disposition", disposition)
+	}
+	http.ServeContent(c.Writer, c.Request, path.Base(key), time.Time{}, obj)
+}
+
+// Upload godoc
+// @Summary Envia um objeto por link assinado
+// @Description Equivalente ao PUT na URL pré-assinada do S3 quando STORAGE_BACKEND é local ou memory. Content-Type e Content-Length devem ser os informados ao gerar o link.
+// @Tags Storage
+// @Accept octet-stream
+// @Param key path string true "Chave do objeto"
+// @Param expires query int true "Validade (unix)"
+// @Param signature query string true "Assinatura HMAC"
+// @Success 200
+// @Failure 403 {object} map[string]string "Link inválido, expirado ou com Content-Type/tamanho diferentes"
+// @Router /storage/{key} [put]
+func (h *StorageHandler) Upload(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+
+	err := h.Store.PutSigned(key, c.Request.URL.Query(), c.GetHeader("Content-Type"), c.Request.ContentLength, c.Request.Body)
+	if err != nil {
+		c.JSON(storageErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func storageErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, storage.ErrInvalidSignature):
+		return http.StatusForbidden
+	case errors.Is(err, storage.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, storage.ErrInvalidKey), errors.Is(err, storage.ErrSizeMismatch):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,282 @@
+package handler
+
+import (
+	"errors"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// As partes vão em streaming para o S3, que exige no mínimo 5 MB por parte
+// (exceto a última); o máximo limita quanto tempo uma requisição fica aberta.
+const (
+	minPartSize = usecase.MinPartSize
+	maxPartSize = 64 << 20
+)
+
+type UploadHandler struct {
+	UploadUC *usecase.UploadUseCase
+}
+
+func NewUploadHandler(uploadUC *usecase.UploadUseCase) *UploadHandler {
+	return &UploadHandler{UploadUC: uploadUC}
+}
+
+type InitiateUploadRequest struct {
+	FileName string `json:"file_name" binding:"required"`
+}
+
+// InitiateUpload godoc
+// @Summary Inicia um upload em partes
+// @Description Cria o vídeo em estado UPLOADING e abre um upload multipart no S3. As partes são enviadas em seguida e o processamento só é disparado no complete.
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body InitiateUploadRequest true "Nome do arquivo (.mp4, .mkv, .avi)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 415 {object} map[string]string "Formato não aceito"
+// @Failure 403 {object} map[string]string "Cota de armazenamento do plano excedida ou e-mail não confirmado"
+// @Failure 429 {object} map[string]string "Limite de vídeos em andamento ou de envios por hora atingido"
+// @Router /api/uploads [post]
+func (h *UploadHandler) InitiateUpload(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req InitiateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	video, err := h.UploadUC.InitiateUpload(userID, req.FileName)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"video_id":      video.ID,
+		"status":        video.Status,
+		"min_part_size": minPartSize,
+		"max_part_size": maxPartSize,
+	})
+}
+
+// UploadPart godoc
+// @Summary Envia uma parte do upload
+// @Description Recebe o corpo bruto da parte, com Content-Length obrigatório. Toda parte, exceto a última, precisa ter ao menos min_part_size bytes. Reenviar o mesmo número substitui a parte anterior, permitindo retomar após falhas.
+// @Tags Uploads
+// @Accept application/octet-stream
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Param part path int true "Número da parte (1-10000)"
+// @Success 200 {object} entity.UploadPart
+// @Failure 400 {object} map[string]string "Parte vazia ou parte intermediária menor que o mínimo"
+// @Failure 411 {object} map[string]string "Content-Length não informado"
+// @Failure 413 {object} map[string]string "Parte maior que o permitido ou limite do arquivo excedido"
+// @Failure 415 {object} map[string]string "Conteúdo da primeira parte não é um vídeo aceito"
+// @Failure 403 {object} map[string]string "Cota de armazenamento do plano excedida"
+// @Router /api/uploads/{id}/parts/{part} [put]
+func (h *UploadHandler) UploadPart(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	partNumber, err := strconv.ParseInt(c.Param("part"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Número da parte inválido"})
+		return
+	}
+
+	// O tamanho precisa ser conhecido antes de ler o corpo: ele é repassado
+	// ao S3 e conferido contra os limites sem guardar a parte em memória.
+	size := c.Request.ContentLength
+	switch {
+	case size < 0:
+		c.JSON(http.StatusLengthRequired, gin.H{"error": "Content-Length obrigatório"})
+		return
+	case size == 0:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parte vazia"})
+		return
+	case size > maxPartSize:
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Parte maior que o permitido"})
+		return
+	}
+
+	part, err := h.UploadUC.UploadPart(userID, videoID, int32(partNumber), io.LimitReader(c.Request.Body, size), size)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, part)
+}
+
+// ListParts godoc
+// @Summary Lista as partes já recebidas
+// @Description Usado pelo cliente para descobrir de qual parte retomar o upload.
+// @Tags Uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {array} entity.UploadPart
+// @Router /api/uploads/{id} [get]
+func (h *UploadHandler) ListParts(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	parts, err := h.UploadUC.ListParts(userID, videoID)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, parts)
+}
+
+// CompleteUpload godoc
+// @Summary Finaliza o upload em partes
+// @Description Monta o objeto no S3 a partir das partes enviadas e envia o vídeo para a fila de processamento.
+// @Tags Uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 202 {object} map[string]string
+// @Failure 422 {object} map[string]string "Duração ou resolução acima do limite"
+// @Router /api/uploads/{id}/complete [post]
+func (h *UploadHandler) CompleteUpload(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	video, err := h.UploadUC.CompleteUpload(userID, videoID)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Upload concluído",
+		"video_id": video.ID,
+		"status":   video.Status,
+	})
+}
+
+// AbortUpload godoc
+// @Summary Cancela um upload em partes
+// @Tags Uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {object} map[string]string
+// @Router /api/uploads/{id} [delete]
+func (h *UploadHandler) AbortUpload(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	if err := h.UploadUC.AbortUpload(userID, videoID); err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Upload cancelado"})
+}
+
+type DirectUploadRequest struct {
+	FileName    string `json:"file_name" binding:"required"`
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,gt=0"`
+}
+
+// RequestDirectUpload godoc
+// @Summary Gera URL pré-assinada para upload direto ao S3
+// @Description Cria o vídeo em estado UPLOADING e devolve uma URL de PUT que expira conforme PRESIGN_EXPIRY (padrão de 15 minutos). O PUT deve usar exatamente o Content-Type e o tamanho informados. Depois do envio, chame /api/videos/{id}/complete.
+// @Tags Uploads
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DirectUploadRequest true "Dados do arquivo"
+// @Success 201 {object} map[string]string
+// @Failure 413 {object} map[string]string "Arquivo maior que o permitido"
+// @Failure 415 {object} map[string]string "Formato não aceito"
+// @Failure 403 {object} map[string]string "Cota de armazenamento do plano excedida ou e-mail não confirmado"
+// @Failure 429 {object} map[string]string "Limite de vídeos em andamento ou de envios por hora atingido"
+// @Router /api/videos/presign [post]
+func (h *UploadHandler) RequestDirectUpload(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var req DirectUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos: " + err.Error()})
+		return
+	}
+
+	video, url, err := h.UploadUC.RequestDirectUpload(userID, req.FileName, req.ContentType, req.Size)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"video_id":   video.ID,
+		"status":     video.Status,
+		"upload_url": url,
+		"method":     http.MethodPut,
+	})
+}
+
+// ConfirmDirectUpload godoc
+// @Summary Confirma o upload direto ao S3
+// @Description Verifica que o arquivo existe no bucket, confere pelo conteúdo que é um vídeo aceito e envia o vídeo para a fila de processamento.
+// @Tags Uploads
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 202 {object} map[string]string
+// @Failure 415 {object} map[string]string "Conteúdo não é um vídeo aceito"
+// @Failure 422 {object} map[string]string "Duração ou resolução acima do limite"
+// @Router /api/videos/{id}/complete [post]
+func (h *UploadHandler) ConfirmDirectUpload(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	video, err := h.UploadUC.ConfirmDirectUpload(userID, videoID)
+	if err != nil {
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":  "Upload concluído",
+		"video_id": video.ID,
+		"status":   video.Status,
+	})
+}
+
+// uploadErrorStatus separa formato não aceito (415) e arquivo acima do limite
+// (413) dos demais erros de validação.
+func uploadErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidOptions):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrUnsupportedFormat):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, usecase.ErrFileTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, usecase.ErrVideoLimits):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, usecase.ErrQuotaExceeded), errors.Is(err, usecase.ErrEmailNotVerified):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrRateLimited):
+		return http.StatusTooManyRequests
+	case errors.Is(err, usecase.ErrAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
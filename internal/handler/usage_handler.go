@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"hackaton-service-api/internal/usecase"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UsageHandler struct {
+	Quota *usecase.QuotaService
+}
+
+func NewUsageHandler(quota *usecase.QuotaService) *UsageHandler {
+	return &UsageHandler{Quota: quota}
+}
+
+// GetUsage godoc
+// @Summary Consumo do usuário em relação aos limites do plano
+// @Description Armazenamento ocupado pelos vídeos (enviado + ZIP), vídeos em andamento e envios na última hora. Limite 0 significa ilimitado.
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} usecase.Usage
+// @Router /api/me/usage [get]
+func (h *UsageHandler) GetUsage(c *gin.Context) {
+	usage, err := h.Quota.UsageFor(c.GetString("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
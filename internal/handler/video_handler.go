@@ -1,8 +1,16 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
 	"hackaton-service-api/internal/usecase"
+	"mime"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -15,15 +23,51 @@ func NewVideoHandler(videoUC *usecase.VideoUseCase) *VideoHandler {
 	return &VideoHandler{VideoUC: videoUC}
 }
 
+// ProcessingOptionsForm são as opções de extração aceitas junto do arquivo.
+// MaxFrames é ponteiro porque max_frames=0 (sem limite) enviado é diferente
+// de omitido.
+type ProcessingOptionsForm struct {
+	PresetID      string  `form:"preset_id"`
+	FrameInterval float64 `form:"frame_interval"`
+	OutputFormat  string  `form:"output_format"`
+	MaxFrames     *int    `form:"max_frames"`
+}
+
+func (f ProcessingOptionsForm) toRequest() usecase.ProcessingRequest {
+	request := usecase.ProcessingRequest{
+		PresetID: f.PresetID,
+		Options: entity.ProcessingOptions{
+			FrameInterval: f.FrameInterval,
+			OutputFormat:  f.OutputFormat,
+		},
+	}
+	if f.MaxFrames != nil {
+		request.Options.MaxFrames = *f.MaxFrames
+		request.MaxFramesSet = true
+	}
+	return request
+}
+
 // UploadVideo godoc
 // @Summary Realiza o upload de um vídeo
-// @Description Faz o upload de um arquivo de vídeo para processamento
+// @Description Faz o upload de um arquivo de vídeo para processamento. O formato é identificado pelo conteúdo (MP4, MKV ou AVI), não pela extensão. As opções de processamento são opcionais: as informadas têm prioridade sobre as do preset (preset_id ou, sem ele, o preset padrão do usuário), e as restantes usam o padrão do sistema (1 frame por segundo, PNG, sem limite de frames).
 // @Tags Videos
 // @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param video formData file true "Arquivo de vídeo (.mp4, .mkv, .avi)"
-// @Success 202 {object} map[string]string
+// @Param preset_id formData string false "ID de um preset do usuário"
+// @Param frame_interval formData number false "Segundos entre frames extraídos (0.1 a 3600)"
+// @Param output_format formData string false "Formato dos frames: png ou jpeg"
+// @Param max_frames formData int false "Máximo de frames extraídos (0 = sem limite, até 10000)"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Opções de processamento inválidas"
+// @Failure 404 {object} map[string]string "Preset não encontrado"
+// @Failure 413 {object} map[string]string "Arquivo maior que o permitido"
+// @Failure 415 {object} map[string]string "Formato não aceito"
+// @Failure 422 {object} map[string]string "Duração ou resolução acima do limite"
+// @Failure 403 {object} map[string]string "Cota de armazenamento do plano excedida ou e-mail não confirmado"
+// @Failure 429 {object} map[string]string "Limite de vídeos em andamento ou de envios por hora atingido"
 // @Router /api/upload [post]
 func (h *VideoHandler) UploadVideo(c *gin.Context) {
 	userID := c.GetString("userID")
@@ -34,6 +78,12 @@ func (h *VideoHandler) UploadVideo(c *gin.Context) {
 		return
 	}
 
+	var form ProcessingOptionsForm
+	if err := c.ShouldBind(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Opções de processamento inválidas: " + err.Error()})
+		return
+	}
+
 	file, err := fileHeader.Open()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao abrir arquivo"})
@@ -41,9 +91,9 @@ func (h *VideoHandler) UploadVideo(c *gin.Context) {
 	}
 	defer file.Close()
 
-	video, err := h.VideoUC.RequestUpload(userID, fileHeader.Filename, file)
+	video, err := h.VideoUC.RequestUpload(userID, fileHeader.Filename, file, fileHeader.Size, form.toRequest())
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(uploadErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -51,40 +101,128 @@ func (h *VideoHandler) UploadVideo(c *gin.Context) {
 		"message":  "Upload iniciado",
 		"video_id": video.ID,
 		"status":   video.Status,
+		"options":  video.Options,
 	})
 }
 
+type ListVideosRequest struct {
+	Status      string `form:"status"`
+	CreatedFrom string `form:"created_from"`
+	CreatedTo   string `form:"created_to"`
+	FileName    string `form:"file_name"`
+	BatchID     string `form:"batch_id"`
+	Sort        string `form:"sort"`
+	Limit       int    `form:"limit"`
+	Cursor      string `form:"cursor"`
+}
+
 // ListVideos godoc
 // @Summary Lista vídeos do usuário
-// @Description Retorna todos os vídeos enviados pelo usuário logado
+// @Description Retorna os vídeos do usuário logado em páginas. Para a próxima página, repita a chamada com o next_cursor recebido (e os mesmos filtros). O total de vídeos que atendem aos filtros vem no header X-Total-Count.
 // @Tags Videos
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} entity.Video
+// @Param status query string false "Status separados por vírgula (ex.: PENDING,PROCESSING)"
+// @Param created_from query string false "Criados a partir de (RFC 3339 ou AAAA-MM-DD, inclusivo)"
+// @Param created_to query string false "Criados antes de (RFC 3339 ou AAAA-MM-DD, exclusivo)"
+// @Param file_name query string false "Trecho do nome do arquivo"
+// @Param batch_id query string false "Somente vídeos do lote"
+// @Param sort query string false "Ordenação" Enums(-created_at, created_at, -updated_at, updated_at) default(-created_at)
+// @Param limit query int false "Itens por página (máx. 100)" default(20)
+// @Param cursor query string false "Cursor devolvido na página anterior"
+// @Success 200 {object} usecase.VideoPage
+// @Header 200 {integer} X-Total-Count "Total de vídeos que atendem aos filtros"
+// @Failure 400 {object} map[string]string "Parâmetros inválidos"
 // @Router /api/videos [get]
 func (h *VideoHandler) ListVideos(c *gin.Context) {
 	userID := c.GetString("userID")
-	
-	videos, err := h.VideoUC.ListByUser(userID)
+
+	var req ListVideosRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Parâmetros inválidos: " + err.Error()})
+		return
+	}
+
+	query := usecase.ListVideosQuery{
+		FileName: req.FileName,
+		BatchID:  req.BatchID,
+		Sort:     req.Sort,
+		Limit:    req.Limit,
+		Cursor:   req.Cursor,
+	}
+	if req.Status != "" {
+		query.Statuses = strings.Split(req.Status, ",")
+	}
+
+	var err error
+	if query.CreatedFrom, err = parseDateParam(req.CreatedFrom); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "created_from inválido"})
+		return
+	}
+	if query.CreatedTo, err = parseDateParam(req.CreatedTo); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "created_to inválido"})
+		return
+	}
+
+	page, err := h.VideoUC.ListByUser(userID, query)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(page.Total, 10))
+	c.JSON(http.StatusOK, page)
+}
+
+// parseDateParam aceita data e hora em RFC 3339 ou apenas a data (meia-noite UTC).
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// GetVideo godoc
+// @Summary Detalhe de um vídeo
+// @Description Retorna o vídeo com os metadados de processamento: tamanho e tipo do arquivo enviado, duração do processamento, tamanho do ZIP e quantidade de frames informados pelo worker, e se o download já está disponível.
+// @Tags Videos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {object} usecase.VideoDetail
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Router /api/videos/{id} [get]
+func (h *VideoHandler) GetVideo(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	detail, err := h.VideoUC.GetDetail(userID, videoID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, videos)
+	c.JSON(http.StatusOK, detail)
 }
 
 // GetDownloadLink godoc
 // @Summary Gera link para download do vídeo processado
-// @Description Retorna uma URL assinada do S3 para baixar o arquivo ZIP com os frames. O vídeo deve estar com status 'DONE'.
+// @Description Retorna uma URL assinada do S3 para baixar o arquivo ZIP com os frames, salvo com o nome do vídeo enviado. O vídeo deve estar com status 'DONE'. Com redirect=true responde 302 para a URL, para download direto pelo navegador ou por curl -L.
 // @Tags Videos
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "ID do Vídeo"
+// @Param redirect query bool false "Redireciona (302) para a URL em vez de devolvê-la"
 // @Success 200 {object} map[string]string "link: http://s3.url..."
-// @Failure 401 {object} map[string]string "Acesso negado"
+// @Success 302 "Redirecionamento para a URL assinada"
+// @Failure 401 {object} map[string]string "Token ausente ou inválido"
+// @Failure 403 {object} map[string]string "Acesso negado"
 // @Failure 404 {object} map[string]string "Vídeo não encontrado"
-// @Failure 422 {object} map[string]string "Vídeo não está pronto"
+// @Failure 409 {object} map[string]string "Vídeo não está pronto"
 // @Router /api/videos/{id}/download [get]
 func (h *VideoHandler) GetDownloadLink(c *gin.Context) {
 	userID := c.GetString("userID")
@@ -92,9 +230,230 @@ func (h *VideoHandler) GetDownloadLink(c *gin.Context) {
 
 	url, err := h.VideoUC.GenerateDownloadURL(userID, videoID)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if redirect, _ := strconv.ParseBool(c.Query("redirect")); redirect {
+		// O link expira; o redirecionamento não pode ficar em cache.
+		c.Header("Cache-Control", "no-store")
+		c.Redirect(http.StatusFound, url)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"download_url": url})
+}
+
+// DownloadArchive godoc
+// @Summary Baixa vários vídeos processados em um único ZIP
+// @Description Monta sob demanda um ZIP com os ZIPs de frames dos vídeos informados em ids (separados por vírgula, todos em DONE, até 50) ou dos vídeos em DONE do lote batch_id. O conteúdo é transmitido sem ficar em memória. O arquivo é o mesmo enquanto os vídeos não mudarem, então aceita Range e If-Range para retomar downloads interrompidos.
+// @Tags Videos
+// @Produce application/zip
+// @Security BearerAuth
+// @Param ids query string false "IDs dos vídeos, separados por vírgula"
+// @Param batch_id query string false "ID do lote"
+// @Param Range header string false "Trecho a retomar (ex.: bytes=1048576-)"
+// @Success 200 {file} file
+// @Success 206 {file} file "Trecho pedido em Range"
+// @Failure 400 {object} map[string]string "Nenhum vídeo informado, ids e batch_id juntos ou vídeos demais"
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado ou lote sem vídeos prontos"
+// @Failure 409 {object} map[string]string "Vídeo não está pronto"
+// @Failure 416 {object} map[string]string "Range fora do arquivo"
+// @Router /api/videos/archive [get]
+func (h *VideoHandler) DownloadArchive(c *gin.Context) {
+	userID := c.GetString("userID")
+
+	var ids []string
+	for _, value := range c.QueryArray("ids") {
+		ids = append(ids, strings.Split(value, ",")...)
+	}
+
+	archive, err := h.VideoUC.PrepareArchive(userID, usecase.ArchiveRequest{VideoIDs: ids, BatchID: c.Query("batch_id")})
+	if err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "application/zip")
+	header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": archive.FileName}))
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("ETag", archive.ETag)
+	header.Set("Last-Modified", archive.ModTime.Format(http.TimeFormat))
+
+	status, start, length := http.StatusOK, int64(0), archive.Size
+	if rangeHeader := c.GetHeader("Range"); rangeHeader != "" && ifRangeMatches(c.GetHeader("If-Range"), archive) {
+		var ok bool
+		start, length, ok = parseByteRange(rangeHeader, archive.Size)
+		switch {
+		case !ok:
+			start, length = 0, archive.Size
+		case length == 0:
+			header.Set("Content-Range", fmt.Sprintf("bytes */%d", archive.Size))
+			c.JSON(http.StatusRequestedRangeNotSatisfiable, gin.H{"error": "Range fora do arquivo"})
+			return
+		default:
+			status = http.StatusPartialContent
+			header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, start+length-1, archive.Size))
+		}
+	}
+
+	header.Set("Content-Length", strconv.FormatInt(length, 10))
+	c.Status(status)
+	if c.Request.Method == http.MethodHead {
+		return
+	}
+
+	// Com os cabeçalhos já enviados, uma falha só pode encurtar a resposta: o
+	// servidor fecha a conexão e o cliente, pelo Content-Length, sabe que
+	// precisa retomar por Range.
+	if err := archive.WriteRange(c.Writer, start, length); err != nil {
+		_ = c.Error(err)
+	}
+}
+
+// ifRangeMatches aceita o Range quando não há If-Range ou quando ele
+// corresponde à versão atual do ZIP, por ETag ou por data.
+func ifRangeMatches(ifRange string, archive *usecase.Archive) bool {
+	if ifRange == "" || ifRange == archive.ETag {
+		return true
+	}
+	modified, err := http.ParseTime(ifRange)
+	return err == nil && !archive.ModTime.After(modified)
+}
+
+// parseByteRange interpreta um único intervalo "bytes=início-fim",
+// "bytes=início-" ou "bytes=-sufixo". Cabeçalhos inválidos ou com vários
+// intervalos devolvem ok=false e o arquivo vai inteiro; um intervalo fora do
+// arquivo devolve length zero.
+func parseByteRange(header string, size int64) (start, length int64, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, 0, false
+	}
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, 0, false
+	}
+
+	if first == "" {
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix < 0 {
+			return 0, 0, false
+		}
+		suffix = min(suffix, size)
+		return size - suffix, suffix, true
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 {
+		return 0, 0, false
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false
+		}
+		end = min(end, size-1)
+	}
+	if start >= size {
+		return 0, 0, true
+	}
+	return start, end - start + 1, true
+}
+
+// RetryVideo godoc
+// @Summary Reprocessa um vídeo com erro
+// @Description Devolve à fila um vídeo em ERROR usando o arquivo já enviado, sem novo upload. O status volta para PENDING, a mensagem de erro é limpa e retry_attempts é incrementado.
+// @Tags Videos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 202 {object} entity.Video
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Failure 409 {object} map[string]string "Vídeo não está em ERROR"
+// @Failure 410 {object} map[string]string "Arquivo original não está mais disponível"
+// @Failure 429 {object} map[string]string "Limite de vídeos em andamento atingido"
+// @Router /api/videos/{id}/retry [post]
+func (h *VideoHandler) RetryVideo(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	video, err := h.VideoUC.Retry(userID, videoID)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, video)
+}
+
+// GetHistory godoc
+// @Summary Histórico de status do vídeo
+// @Description Retorna todas as transições de status do vídeo, com data, autor e motivo.
+// @Tags Videos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {array} entity.VideoStatusEvent
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Router /api/videos/{id}/history [get]
+func (h *VideoHandler) GetHistory(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	events, err := h.VideoUC.GetHistory(userID, videoID)
+	if err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// DeleteVideo godoc
+// @Summary Remove um vídeo
+// @Description Cancela o vídeo se ainda estiver em UPLOADING ou PENDING (o worker passa a ignorá-lo) e o remove da listagem. O arquivo enviado e o ZIP são apagados do S3 após a janela de retenção. Vídeos em PROCESSING não podem ser removidos.
+// @Tags Videos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do Vídeo"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string "Acesso negado"
+// @Failure 404 {object} map[string]string "Vídeo não encontrado"
+// @Failure 409 {object} map[string]string "Vídeo em processamento"
+// @Router /api/videos/{id} [delete]
+func (h *VideoHandler) DeleteVideo(c *gin.Context) {
+	userID := c.GetString("userID")
+	videoID := c.Param("id")
+
+	if err := h.VideoUC.Delete(userID, videoID); err != nil {
+		c.JSON(videoErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Vídeo removido"})
+}
+
+func videoErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrAccessDenied):
+		return http.StatusForbidden
+	case errors.Is(err, usecase.ErrInvalidListQuery), errors.Is(err, usecase.ErrInvalidArchive):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrVideoProcessing), errors.Is(err, usecase.ErrVideoNotRetryable), errors.Is(err, usecase.ErrVideoNotReady), errors.Is(err, repository.ErrConcurrentUpdate):
+		return http.StatusConflict
+	case errors.Is(err, usecase.ErrInputUnavailable):
+		return http.StatusGone
+	case errors.Is(err, usecase.ErrRateLimited):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
 }
\ No newline at end of file
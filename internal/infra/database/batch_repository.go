@@ -0,0 +1,38 @@
+package database
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type BatchRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.BatchRepository = (*BatchRepositoryGorm)(nil)
+
+func NewBatchRepository(db *gorm.DB) *BatchRepositoryGorm {
+	return &BatchRepositoryGorm{DB: db}
+}
+
+func (r *BatchRepositoryGorm) Create(batch *entity.Batch) error {
+	return r.DB.Create(batch).Error
+}
+
+func (r *BatchRepositoryGorm) FindByID(id string) (*entity.Batch, error) {
+	var batch entity.Batch
+	err := r.DB.First(&batch, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
+	return &batch, err
+}
+
+func (r *BatchRepositoryGorm) FindRecentByUserID(userID string, limit int) ([]entity.Batch, error) {
+	var batches []entity.Batch
+	err := r.DB.Where("user_id = ?", userID).Order("created_at desc").Limit(limit).Find(&batches).Error
+	return batches, err
+}
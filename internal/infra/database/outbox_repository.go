@@ -0,0 +1,49 @@
+package database
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OutboxRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.OutboxRepository = (*OutboxRepositoryGorm)(nil)
+
+func NewOutboxRepository(db *gorm.DB) *OutboxRepositoryGorm {
+	return &OutboxRepositoryGorm{DB: db}
+}
+
+// ClaimDue usa SKIP LOCKED para que réplicas concorrentes peguem lotes
+// disjuntos. Se o relay cair depois de publicar e antes do Update, a mensagem
+// volta após o lease e é publicada de novo (at-least-once).
+func (r *OutboxRepositoryGorm) ClaimDue(now time.Time, lease time.Duration, limit int) ([]entity.OutboxMessage, error) {
+	var messages []entity.OutboxMessage
+	err := r.DB.Raw(`UPDATE outbox SET next_attempt_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE sent_at IS NULL AND next_attempt_at <= ?
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT ?
+		)
+		RETURNING *`, now.Add(lease), now, limit).Scan(&messages).Error
+
+	// RETURNING não preserva a ordem da subconsulta.
+	slices.SortFunc(messages, func(a, b entity.OutboxMessage) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return messages, err
+}
+
+func (r *OutboxRepositoryGorm) Update(message *entity.OutboxMessage) error {
+	return r.DB.Model(message).Select("attempts", "next_attempt_at", "last_error", "sent_at").Updates(message).Error
+}
+
+func (r *OutboxRepositoryGorm) DeleteSentBefore(before time.Time) (int64, error) {
+	result := r.DB.Where("sent_at IS NOT NULL AND sent_at < ?", before).Delete(&entity.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,32 @@
+package database
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type PlanRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.PlanRepository = (*PlanRepositoryGorm)(nil)
+
+func NewPlanRepository(db *gorm.DB) *PlanRepositoryGorm {
+	return &PlanRepositoryGorm{DB: db}
+}
+
+func (r *PlanRepositoryGorm) FindByID(id string) (*entity.Plan, error) {
+	var plan entity.Plan
+	err := r.DB.First(&plan, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
+	return &plan, err
+}
+
+func (r *PlanRepositoryGorm) EnsureExists(plan *entity.Plan) error {
+	return r.DB.FirstOrCreate(plan).Error
+}
@@ -7,9 +7,15 @@ import (
 	"log"
 )
 
-func SetupDatabase(host, user, password, dbName, sslmode string) *gorm.DB {
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=5432 sslmode=%s TimeZone=America/Sao_Paulo", 
+// BuildDSN monta a string de conexão usada tanto pelo GORM quanto pela
+// conexão dedicada ao LISTEN de eventos.
+func BuildDSN(host, user, password, dbName, sslmode string) string {
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=5432 sslmode=%s TimeZone=America/Sao_Paulo", 
 		host, user, password, dbName, sslmode)
+}
+
+func SetupDatabase(host, user, password, dbName, sslmode string) *gorm.DB {
+	dsn := BuildDSN(host, user, password, dbName, sslmode)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
 	if err != nil {
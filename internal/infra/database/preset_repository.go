@@ -0,0 +1,79 @@
+package database
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+type PresetRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.PresetRepository = (*PresetRepositoryGorm)(nil)
+
+func NewPresetRepository(db *gorm.DB) *PresetRepositoryGorm {
+	return &PresetRepositoryGorm{DB: db}
+}
+
+func (r *PresetRepositoryGorm) Create(preset *entity.Preset) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultPreset(tx, preset); err != nil {
+			return err
+		}
+		return tx.Create(preset).Error
+	})
+}
+
+func (r *PresetRepositoryGorm) Update(preset *entity.Preset) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := clearDefaultPreset(tx, preset); err != nil {
+			return err
+		}
+		return tx.Save(preset).Error
+	})
+}
+
+func (r *PresetRepositoryGorm) FindByID(id string) (*entity.Preset, error) {
+	return r.first(r.DB.Where("id = ?", id))
+}
+
+func (r *PresetRepositoryGorm) FindByName(userID, name string) (*entity.Preset, error) {
+	return r.first(r.DB.Where("user_id = ? AND name = ?", userID, name))
+}
+
+func (r *PresetRepositoryGorm) FindDefault(userID string) (*entity.Preset, error) {
+	return r.first(r.DB.Where("user_id = ? AND is_default", userID))
+}
+
+func (r *PresetRepositoryGorm) FindAllByUserID(userID string) ([]entity.Preset, error) {
+	var presets []entity.Preset
+	err := r.DB.Where("user_id = ?", userID).Order("name asc").Find(&presets).Error
+	return presets, err
+}
+
+func (r *PresetRepositoryGorm) Delete(preset *entity.Preset) error {
+	return r.DB.Delete(preset).Error
+}
+
+func (r *PresetRepositoryGorm) first(query *gorm.DB) (*entity.Preset, error) {
+	var preset entity.Preset
+	err := query.First(&preset).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
+	return &preset, err
+}
+
+// clearDefaultPreset garante um único padrão por usuário; o índice único
+// parcial em is_default rejeita o que escapar daqui.
+func clearDefaultPreset(tx *gorm.DB, preset *entity.Preset) error {
+	if !preset.IsDefault {
+		return nil
+	}
+	return tx.Model(&entity.Preset{}).
+		Where("user_id = ? AND id <> ? AND is_default", preset.UserID, preset.ID).
+		Update("is_default", false).Error
+}
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.SessionRepository = (*SessionRepositoryGorm)(nil)
+
+func NewSessionRepository(db *gorm.DB) *SessionRepositoryGorm {
+	return &SessionRepositoryGorm{DB: db}
+}
+
+func (r *SessionRepositoryGorm) Create(session *entity.Session) error {
+	return r.DB.Create(session).Error
+}
+
+func (r *SessionRepositoryGorm) FindByID(id string) (*entity.Session, error) {
+	var session entity.Session
+	err := r.DB.First(&session, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByTokenHash também casa com o hash anterior, para que o use case consiga
+// detectar a reutilização de um refresh token já rotacionado.
+func (r *SessionRepositoryGorm) FindByTokenHash(hash string) (*entity.Session, error) {
+	var session entity.Session
+	err := r.DB.Where("refresh_token_hash = ? OR previous_token_hash = ?", hash, hash).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *SessionRepositoryGorm) FindActiveByUserID(userID string) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := r.DB.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at desc").Find(&sessions).Error
+	return sessions, err
+}
+
+// Rotate é um UPDATE condicional: de duas renovações simultâneas com o mesmo
+// token, só uma troca o hash.
+func (r *SessionRepositoryGorm) Rotate(session *entity.Session, currentHash string) (bool, error) {
+	result := r.DB.Model(&entity.Session{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", session.ID, currentHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash":  session.RefreshTokenHash,
+			"previous_token_hash": session.PreviousTokenHash,
+			"last_used_at":        session.LastUsedAt,
+			"expires_at":          session.ExpiresAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// Revoke só grava revoked_at, sem regravar a sessão lida antes.
+func (r *SessionRepositoryGorm) Revoke(id string, at time.Time) error {
+	return r.DB.Model(&entity.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *SessionRepositoryGorm) RevokeAllByUserID(userID string) error {
+	return r.DB.Model(&entity.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ShareRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.ShareRepository = (*ShareRepositoryGorm)(nil)
+
+func NewShareRepository(db *gorm.DB) *ShareRepositoryGorm {
+	return &ShareRepositoryGorm{DB: db}
+}
+
+func (r *ShareRepositoryGorm) Create(share *entity.Share) error {
+	return r.DB.Create(share).Error
+}
+
+func (r *ShareRepositoryGorm) FindByID(id string) (*entity.Share, error) {
+	return r.findOne("id = ?", id)
+}
+
+func (r *ShareRepositoryGorm) FindByTokenHash(hash string) (*entity.Share, error) {
+	return r.findOne("token_hash = ?", hash)
+}
+
+func (r *ShareRepositoryGorm) findOne(query string, args ...interface{}) (*entity.Share, error) {
+	var share entity.Share
+	err := r.DB.Where(query, args...).First(&share).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
+	return &share, err
+}
+
+func (r *ShareRepositoryGorm) FindAllByVideoID(videoID string) ([]entity.Share, error) {
+	var shares []entity.Share
+	err := r.DB.Where("video_id = ?", videoID).Order("created_at desc").Find(&shares).Error
+	return shares, err
+}
+
+// Revoke não regrava o link inteiro, para não desfazer downloads contados
+// pelo RegisterDownload depois da leitura.
+func (r *ShareRepositoryGorm) Revoke(id string, at time.Time) error {
+	return r.DB.Model(&entity.Share{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+// RegisterDownload confere e incrementa o contador no mesmo UPDATE, para que
+// downloads simultâneos não ultrapassem max_downloads.
+func (r *ShareRepositoryGorm) RegisterDownload(id string, at time.Time) (bool, error) {
+	result := r.DB.Model(&entity.Share{}).
+		Where("id = ? AND revoked_at IS NULL AND expires_at > ?", id, at).
+		Where("max_downloads = 0 OR download_count < max_downloads").
+		Updates(map[string]interface{}{
+			"download_count":    gorm.Expr("download_count + 1"),
+			"last_download_at":  at,
+			"password_failures": 0,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+// RegisterPasswordFailure incrementa no próprio UPDATE, para que tentativas
+// simultâneas não se percam na contagem. Os dois CASE leem o valor anterior
+// de password_failures.
+func (r *ShareRepositoryGorm) RegisterPasswordFailure(id string, maxFailures int, lockUntil time.Time) error {
+	return r.DB.Model(&entity.Share{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"password_failures": gorm.Expr("CASE WHEN password_failures + 1 >= ? THEN 0 ELSE password_failures + 1 END", maxFailures),
+			"locked_until":      gorm.Expr("CASE WHEN password_failures + 1 >= ? THEN ? ELSE locked_until END", maxFailures, lockUntil),
+		}).Error
+}
@@ -0,0 +1,37 @@
+package database
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UploadPartRepositoryGorm struct {
+	DB *gorm.DB
+}
+
+var _ repository.UploadPartRepository = (*UploadPartRepositoryGorm)(nil)
+
+func NewUploadPartRepository(db *gorm.DB) *UploadPartRepositoryGorm {
+	return &UploadPartRepositoryGorm{DB: db}
+}
+
+// Save faz upsert da parte: reenviar o mesmo número substitui o ETag anterior.
+func (r *UploadPartRepositoryGorm) Save(part *entity.UploadPart) error {
+	return r.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "video_id"}, {Name: "part_number"}},
+		DoUpdates: clause.AssignmentColumns([]string{"etag", "size", "created_at"}),
+	}).Create(part).Error
+}
+
+func (r *UploadPartRepositoryGorm) FindAllByVideoID(videoID string) ([]entity.UploadPart, error) {
+	var parts []entity.UploadPart
+	err := r.DB.Where("video_id = ?", videoID).Order("part_number asc").Find(&parts).Error
+	return parts, err
+}
+
+func (r *UploadPartRepositoryGorm) DeleteAllByVideoID(videoID string) error {
+	return r.DB.Where("video_id = ?", videoID).Delete(&entity.UploadPart{}).Error
+}
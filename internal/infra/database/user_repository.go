@@ -3,6 +3,7 @@ package database
 import (
 	"hackaton-service-api/internal/entity"
 	"hackaton-service-api/internal/repository"
+	"time"
 	"gorm.io/gorm"
 )
 
@@ -45,4 +46,27 @@ func (r *UserRepositoryGorm) FindByID(id string) (*entity.User, error) {
 		return nil, err
 	}
 	return &user, nil
-}
\ No newline at end of file
+}
+
+func (r *UserRepositoryGorm) Update(user *entity.User) error {
+	return r.DB.Save(user).Error
+}
+
+// MarkVerificationSent é um UPDATE condicional para que dois pedidos
+// simultâneos não passem ambos pelo intervalo mínimo.
+func (r *UserRepositoryGorm) MarkVerificationSent(userID string, at, sentBefore time.Time) (bool, error) {
+	result := r.DB.Model(&entity.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at < ?)", userID, sentBefore).
+		Update("verification_sent_at", at)
+	return result.RowsAffected == 1, result.Error
+}
+
+// BackfillEmailVerification dá como confirmado o e-mail das contas criadas
+// antes da confirmação existir: só elas não têm nenhum link enviado, já que
+// NewUser marca o envio no cadastro. Pode rodar a cada arranque.
+func (r *UserRepositoryGorm) BackfillEmailVerification() (int64, error) {
+	result := r.DB.Model(&entity.User{}).
+		Where("email_verified_at IS NULL AND verification_sent_at IS NULL").
+		UpdateColumn("email_verified_at", gorm.Expr("created_at"))
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,47 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"hackaton-service-api/internal/infra/database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// offlineConn satisfaz o gorm.ConnPool sem banco: em DryRun o GORM só monta
+// o SQL, e o teste confere a instrução gerada.
+type offlineConn struct{}
+
+func (offlineConn) PrepareContext(context.Context, string) (*sql.Stmt, error) { return nil, nil }
+func (offlineConn) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, nil
+}
+func (offlineConn) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, nil
+}
+func (offlineConn) QueryRowContext(context.Context, string, ...interface{}) *sql.Row { return nil }
+
+func TestUserRepository_BackfillEmailVerification_OnlyPreExistingUsers(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: offlineConn{}}), &gorm.Config{DryRun: true})
+	require.NoError(t, err)
+
+	var statement *gorm.Statement
+	require.NoError(t, db.Callback().Update().After("gorm:update").Register("capture", func(tx *gorm.DB) {
+		statement = tx.Statement
+	}))
+
+	_, err = database.NewUserRepository(db).BackfillEmailVerification()
+	require.NoError(t, err)
+	require.NotNil(t, statement)
+
+	// Contas cadastradas depois da confirmação já têm verification_sent_at e
+	// ficam de fora; as antigas passam a confirmadas na data do cadastro.
+	assert.Equal(t,
+		`UPDATE "users" SET "email_verified_at"=created_at WHERE (email_verified_at IS NULL AND verification_sent_at IS NULL) AND "users"."deleted_at" IS NULL`,
+		statement.SQL.String())
+	assert.Empty(t, statement.Vars)
+}
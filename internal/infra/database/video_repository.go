@@ -1,11 +1,25 @@
 package database
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"hackaton-service-api/internal/entity"
 	"hackaton-service-api/internal/repository"
+	"strings"
+	"time"
 	"gorm.io/gorm"
 )
 
+// VideoStatusChannel é o canal do LISTEN/NOTIFY em que cada transição de
+// status é publicada para as réplicas da API.
+const VideoStatusChannel = "video_status"
+
+const maxNotifyErrorLength = 1024
+
+// likeEscaper faz o filtro por nome tratar % e _ como texto literal.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type VideoRepositoryGorm struct {
 	DB *gorm.DB
 }
@@ -20,18 +34,209 @@ func (r *VideoRepositoryGorm) Create(video *entity.Video) error {
 	return r.DB.Create(video).Error
 }
 
+func (r *VideoRepositoryGorm) CreateWithOutbox(video *entity.Video, message *entity.OutboxMessage) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(video).Error; err != nil {
+			return err
+		}
+		return tx.Create(message).Error
+	})
+}
+
 func (r *VideoRepositoryGorm) FindByID(id string) (*entity.Video, error) {
 	var video entity.Video
 	err := r.DB.First(&video, "id = ?", id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrNotFound
+	}
 	return &video, err
 }
 
-func (r *VideoRepositoryGorm) FindAllByUserID(userID string) ([]entity.Video, error) {
+func (r *VideoRepositoryGorm) FindPage(filter repository.VideoFilter) ([]entity.Video, error) {
+	column, desc := filter.Sort.Column()
+	direction, comparison := "asc", ">"
+	if desc {
+		direction, comparison = "desc", "<"
+	}
+
+	query := applyVideoFilter(r.DB, filter)
+	if filter.After != nil {
+		// Comparação de linha do Postgres: (col, id) > (?, ?) continua exatamente
+		// após o último item, mesmo com datas repetidas.
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, comparison), filter.After.SortValue, filter.After.ID)
+	}
+
+	var videos []entity.Video
+	err := query.
+		Order(fmt.Sprintf("%s %s, id %s", column, direction, direction)).
+		Limit(filter.Limit).
+		Find(&videos).Error
+	return videos, err
+}
+
+func (r *VideoRepositoryGorm) Count(filter repository.VideoFilter) (int64, error) {
+	var total int64
+	err := applyVideoFilter(r.DB.Model(&entity.Video{}), filter).Count(&total).Error
+	return total, err
+}
+
+func (r *VideoRepositoryGorm) Delete(video *entity.Video) error {
+	return r.DB.Delete(video).Error
+}
+
+func (r *VideoRepositoryGorm) FindDeletedBefore(before time.Time, limit int) ([]entity.Video, error) {
 	var videos []entity.Video
-	err := r.DB.Where("user_id = ?", userID).Order("created_at desc").Find(&videos).Error
+	err := r.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at asc").
+		Limit(limit).
+		Find(&videos).Error
 	return videos, err
 }
 
+// FindStale usa updated_at para os vídeos gravados antes de activity_at
+// existir.
+func (r *VideoRepositoryGorm) FindStale(activeBefore, now time.Time, limit int) ([]entity.Video, error) {
+	var videos []entity.Video
+	err := r.DB.
+		Where("status IN ? AND COALESCE(activity_at, updated_at) < ?", []entity.VideoStatus{entity.StatusPending, entity.StatusProcessing}, activeBefore).
+		Where("next_requeue_at IS NULL OR next_requeue_at <= ?", now).
+		Order("COALESCE(activity_at, updated_at) asc").
+		Limit(limit).
+		Find(&videos).Error
+	return videos, err
+}
+
+// FindAbandonedUploads usa o created_at das partes, atualizado a cada reenvio,
+// para não expirar um upload em partes que ainda está recebendo dados.
+func (r *VideoRepositoryGorm) FindAbandonedUploads(idleBefore time.Time, limit int) ([]entity.Video, error) {
+	var videos []entity.Video
+	err := r.DB.
+		Where("status = ? AND created_at < ?", entity.StatusUploading, idleBefore).
+		Where("NOT EXISTS (SELECT 1 FROM upload_parts WHERE upload_parts.video_id = videos.id AND upload_parts.created_at >= ?)", idleBefore).
+		Order("created_at asc").
+		Limit(limit).
+		Find(&videos).Error
+	return videos, err
+}
+
+// Purge apaga o vídeo já removido junto com o histórico, as partes de upload,
+// as mensagens do outbox e os links de compartilhamento.
+func (r *VideoRepositoryGorm) Purge(videoID string) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("video_id = ?", videoID).Delete(&entity.VideoStatusEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("video_id = ?", videoID).Delete(&entity.UploadPart{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("video_id = ?", videoID).Delete(&entity.OutboxMessage{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("video_id = ?", videoID).Delete(&entity.Share{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ?", videoID).Delete(&entity.Video{}).Error
+	})
+}
+
+// Usage soma o consumo em uma única consulta. Unscoped porque os envios
+// recentes incluem vídeos já removidos; os demais agregados os filtram.
+func (r *VideoRepositoryGorm) Usage(userID string, since time.Time) (*repository.VideoUsage, error) {
+	var usage repository.VideoUsage
+	err := r.DB.Unscoped().Model(&entity.Video{}).
+		Select(`COALESCE(SUM(input_size + output_size) FILTER (WHERE deleted_at IS NULL), 0) AS stored_bytes,
+			COUNT(*) FILTER (WHERE deleted_at IS NULL AND status IN ?) AS in_flight,
+			COUNT(*) FILTER (WHERE created_at >= ?) AS uploads_since`, entity.InFlightStatuses, since).
+		Where("user_id = ?", userID).
+		Scan(&usage).Error
+	return &usage, err
+}
+
+func applyVideoFilter(db *gorm.DB, filter repository.VideoFilter) *gorm.DB {
+	query := db.Where("user_id = ?", filter.UserID)
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if !filter.CreatedFrom.IsZero() {
+		query = query.Where("created_at >= ?", filter.CreatedFrom)
+	}
+	if !filter.CreatedTo.IsZero() {
+		query = query.Where("created_at < ?", filter.CreatedTo)
+	}
+	if filter.BatchID != "" {
+		query = query.Where("batch_id = ?", filter.BatchID)
+	}
+	if filter.FileName != "" {
+		query = query.Where("file_name ILIKE ?", "%"+likeEscaper.Replace(filter.FileName)+"%")
+	}
+	return query
+}
+
+// Update grava o vídeo só se a versão lida ainda for a do banco, evitando que
+// uma escrita atrasada sobrescreva outra mais recente.
 func (r *VideoRepositoryGorm) Update(video *entity.Video) error {
-	return r.DB.Save(video).Error
+	return updateVersioned(r.DB, video)
+}
+
+// UpdateStatus grava o vídeo e o evento de histórico na mesma transação e
+// publica a mudança em VideoStatusChannel. O NOTIFY só é entregue no commit,
+// então nenhum cliente é avisado de uma transição que foi desfeita.
+func (r *VideoRepositoryGorm) UpdateStatus(video *entity.Video, event *entity.VideoStatusEvent) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		return updateStatus(tx, video, event)
+	})
+}
+
+func (r *VideoRepositoryGorm) UpdateStatusWithOutbox(video *entity.Video, event *entity.VideoStatusEvent, message *entity.OutboxMessage) error {
+	return r.DB.Transaction(func(tx *gorm.DB) error {
+		if err := updateStatus(tx, video, event); err != nil {
+			return err
+		}
+		return tx.Create(message).Error
+	})
+}
+
+func (r *VideoRepositoryGorm) FindStatusHistory(videoID string) ([]entity.VideoStatusEvent, error) {
+	var events []entity.VideoStatusEvent
+	err := r.DB.Where("video_id = ?", videoID).Order("created_at asc").Find(&events).Error
+	return events, err
+}
+
+func updateStatus(tx *gorm.DB, video *entity.Video, event *entity.VideoStatusEvent) error {
+	if err := updateVersioned(tx, video); err != nil {
+		return err
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+	return notifyStatusChange(tx, entity.NewVideoStatusChange(video, event))
+}
+
+func updateVersioned(db *gorm.DB, video *entity.Video) error {
+	current := video.Version
+	video.Version = current + 1
+
+	result := db.Model(video).Where("version = ?", current).Select("*").Omit("created_at").Updates(video)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = repository.ErrConcurrentUpdate
+	}
+	if result.Error != nil {
+		video.Version = current
+	}
+	return result.Error
+}
+
+func notifyStatusChange(tx *gorm.DB, change entity.VideoStatusChange) error {
+	// O payload do NOTIFY é limitado a 8000 bytes; a mensagem completa
+	// continua disponível no próprio vídeo.
+	if len(change.ErrorMessage) > maxNotifyErrorLength {
+		change.ErrorMessage = strings.ToValidUTF8(change.ErrorMessage[:maxNotifyErrorLength], "")
+	}
+
+	payload, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	return tx.Exec("SELECT pg_notify(?, ?)", VideoStatusChannel, string(payload)).Error
 }
\ No newline at end of file
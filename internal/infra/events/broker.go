@@ -0,0 +1,60 @@
+package events
+
+import (
+	"hackaton-service-api/internal/entity"
+	"sync"
+)
+
+// Mudanças acumuladas por assinante antes de começarem a ser descartadas.
+const subscriberBuffer = 16
+
+// Broker é o pub/sub em memória que entrega mudanças de status às conexões
+// SSE abertas nesta réplica, separadas por usuário.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan entity.VideoStatusChange]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[string]map[chan entity.VideoStatusChange]struct{})}
+}
+
+// Subscribe devolve o canal de eventos do usuário e a função que encerra a
+// assinatura; ela deve ser chamada quando o cliente desconectar.
+func (b *Broker) Subscribe(userID string) (<-chan entity.VideoStatusChange, func()) {
+	ch := make(chan entity.VideoStatusChange, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan entity.VideoStatusChange]struct{})
+	}
+	b.subscribers[userID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[userID], ch)
+			if len(b.subscribers[userID]) == 0 {
+				delete(b.subscribers, userID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish nunca bloqueia: um cliente lento perde eventos em vez de travar os
+// demais (a tela recarrega a lista ao receber o próximo).
+func (b *Broker) Publish(change entity.VideoStatusChange) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[change.UserID] {
+		select {
+		case ch <- change:
+		default:
+		}
+	}
+}
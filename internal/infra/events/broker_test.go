@@ -0,0 +1,84 @@
+package events_test
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/infra/events"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func change(userID, videoID string) entity.VideoStatusChange {
+	return entity.VideoStatusChange{VideoID: videoID, UserID: userID, Status: entity.StatusDone}
+}
+
+func TestBroker_DeliversOnlyToTheUser(t *testing.T) {
+	broker := events.NewBroker()
+	ana, unsubscribeAna := broker.Subscribe("ana")
+	defer unsubscribeAna()
+	anaOtherTab, unsubscribeOtherTab := broker.Subscribe("ana")
+	defer unsubscribeOtherTab()
+	bia, unsubscribeBia := broker.Subscribe("bia")
+	defer unsubscribeBia()
+
+	broker.Publish(change("ana", "v1"))
+
+	for _, ch := range []<-chan entity.VideoStatusChange{ana, anaOtherTab} {
+		select {
+		case got := <-ch:
+			assert.Equal(t, "v1", got.VideoID)
+		case <-time.After(time.Second):
+			t.Fatal("evento não entregue a uma das conexões do usuário")
+		}
+	}
+	select {
+	case got := <-bia:
+		t.Fatalf("evento de outro usuário entregue: %+v", got)
+	default:
+	}
+}
+
+func TestBroker_FullBufferDropsWithoutBlocking(t *testing.T) {
+	broker := events.NewBroker()
+	ch, unsubscribe := broker.Subscribe("ana")
+	defer unsubscribe()
+
+	// Ninguém lê o canal: além do buffer, os eventos são descartados e o
+	// Publish volta na hora.
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 1000; i++ {
+			broker.Publish(change("ana", "v1"))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish bloqueou com o buffer do assinante cheio")
+	}
+
+	received := 0
+	for len(ch) > 0 {
+		<-ch
+		received++
+	}
+	assert.Positive(t, received)
+	assert.Less(t, received, 1000)
+}
+
+func TestBroker_UnsubscribeIsIdempotentAndClosesTheChannel(t *testing.T) {
+	broker := events.NewBroker()
+	ch, unsubscribe := broker.Subscribe("ana")
+
+	unsubscribe()
+	assert.NotPanics(t, unsubscribe)
+
+	_, open := <-ch
+	require.False(t, open, "canal deve ser fechado ao cancelar a assinatura")
+
+	// Sem assinantes, publicar não envia para o canal fechado.
+	assert.NotPanics(t, func() { broker.Publish(change("ana", "v1")) })
+}
@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"hackaton-service-api/internal/entity"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const maxReconnectDelay = 30 * time.Second
+
+// PostgresListener mantém uma conexão dedicada em LISTEN e repassa cada
+// notificação ao Broker local. Como toda réplica da API escuta o mesmo canal,
+// o cliente recebe o evento independentemente de qual réplica fez a mudança.
+type PostgresListener struct {
+	DSN     string
+	Channel string
+	Broker  *Broker
+}
+
+func NewPostgresListener(dsn, channel string, broker *Broker) *PostgresListener {
+	return &PostgresListener{DSN: dsn, Channel: channel, Broker: broker}
+}
+
+// Run bloqueia até o contexto ser cancelado, reconectando com backoff.
+func (l *PostgresListener) Run(ctx context.Context) {
+	delay := time.Second
+	for ctx.Err() == nil {
+		started := time.Now()
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		// Uma conexão que ficou de pé por um tempo não conta como falha seguida.
+		if time.Since(started) > maxReconnectDelay {
+			delay = time.Second
+		}
+
+		log.Printf("[events] conexão LISTEN perdida (%v), reconectando em %s", err, delay)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxReconnectDelay)
+	}
+}
+
+func (l *PostgresListener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.DSN)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.Channel}.Sanitize()); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var change entity.VideoStatusChange
+		if err := json.Unmarshal([]byte(notification.Payload), &change); err != nil {
+			log.Printf("[events] notificação inválida: %v", err)
+			continue
+		}
+		l.Broker.Publish(change)
+	}
+}
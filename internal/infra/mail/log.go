@@ -0,0 +1,55 @@
+package mail
+
+import (
+	"fmt"
+	"hackaton-service-api/internal/usecase"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// LogMailer não envia nada: grava cada mensagem como um arquivo .eml em Dir
+// ou, com Dir vazio, escreve no log. Serve para desenvolvimento e testes,
+// em que o link de confirmação é copiado de lá.
+type LogMailer struct {
+	Dir  string
+	From string
+}
+
+var _ usecase.Mailer = (*LogMailer)(nil)
+
+func NewLogMailer(dir, from string) *LogMailer {
+	return &LogMailer{Dir: dir, From: from}
+}
+
+func (m *LogMailer) Send(message usecase.EmailMessage) error {
+	now := time.Now()
+	body, err := buildMessage(m.From, message, now)
+	if err != nil {
+		return err
+	}
+
+	if m.Dir == "" {
+		log.Printf("[mail] para %s: %s\n%s", message.To, message.Subject, message.Body)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s_%s.eml", now.UTC().Format("20060102T150405.000000000"), fileSafe(message.To))
+	return os.WriteFile(filepath.Join(m.Dir, name), body, 0o600)
+}
+
+func fileSafe(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_', r == '@':
+			return r
+		default:
+			return '_'
+		}
+	}, s)
+}
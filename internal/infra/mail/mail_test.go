@@ -0,0 +1,151 @@
+package mail_test
+
+import (
+	"bufio"
+	"hackaton-service-api/internal/infra/mail"
+	"hackaton-service-api/internal/usecase"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogMailer_WritesMessage(t *testing.T) {
+	dir := t.TempDir()
+	mailer := mail.NewLogMailer(dir, "API <noreply@example.com>")
+
+	require.NoError(t, mailer.Send(usecase.EmailMessage{
+		To:      "ana@example.com",
+		Subject: "Confirme seu e-mail",
+		Body:    "Olá!\n\nhttp://api/verify?token=abc\n",
+	}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	msg, err := netmail.ReadMessage(strings.NewReader(string(raw)))
+	require.NoError(t, err)
+
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Confirme seu e-mail", subject)
+	assert.Equal(t, "ana@example.com", msg.Header.Get("To"))
+
+	body, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	assert.Equal(t, "Olá!\r\n\r\nhttp://api/verify?token=abc\r\n", string(body))
+}
+
+func TestLogMailer_RejectsHeaderInjection(t *testing.T) {
+	mailer := mail.NewLogMailer(t.TempDir(), "noreply@example.com")
+
+	err := mailer.Send(usecase.EmailMessage{To: "ana@example.com\r\nBcc: x@example.com", Subject: "Oi"})
+	assert.ErrorIs(t, err, mail.ErrInvalidHeader)
+}
+
+func TestNewSMTPMailer_ValidatesConfig(t *testing.T) {
+	_, err := mail.NewSMTPMailer("smtp.example.com", "", "", "noreply@example.com")
+	assert.Error(t, err)
+
+	_, err = mail.NewSMTPMailer("smtp.example.com:587", "", "", "sem arroba")
+	assert.Error(t, err)
+
+	_, err = mail.NewSMTPMailer("smtp.example.com:587", "", "", "API <noreply@example.com>")
+	assert.NoError(t, err)
+}
+
+// fakeSMTP atende uma conexão com as respostas mínimas de um servidor SMTP
+// sem STARTTLS e devolve os comandos recebidos.
+func fakeSMTP(t *testing.T) (string, <-chan []string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	commands := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var received []string
+		r := bufio.NewReader(conn)
+		io.WriteString(conn, "220 fake ESMTP\r\n")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				break
+			}
+			line = strings.TrimRight(line, "\r\n")
+			received = append(received, line)
+			cmd := strings.ToUpper(strings.Fields(line + " ")[0])
+			switch cmd {
+			case "EHLO", "HELO":
+				io.WriteString(conn, "250 fake\r\n")
+			case "DATA":
+				io.WriteString(conn, "354 fim com .\r\n")
+				for {
+					data, err := r.ReadString('\n')
+					if err != nil || data == ".\r\n" {
+						break
+					}
+				}
+				io.WriteString(conn, "250 aceito\r\n")
+			case "QUIT":
+				io.WriteString(conn, "221 tchau\r\n")
+				commands <- received
+				return
+			default:
+				io.WriteString(conn, "250 ok\r\n")
+			}
+		}
+		commands <- received
+	}()
+	return ln.Addr().String(), commands
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	addr, commands := fakeSMTP(t)
+	mailer, err := mail.NewSMTPMailer(addr, "", "", "API <noreply@example.com>")
+	require.NoError(t, err)
+
+	require.NoError(t, mailer.Send(usecase.EmailMessage{To: "ana@example.com", Subject: "Oi", Body: "Olá"}))
+
+	assert.Equal(t, []string{"EHLO localhost", "MAIL FROM:<noreply@example.com>", "RCPT TO:<ana@example.com>", "DATA", "QUIT"}, <-commands)
+}
+
+func TestSMTPMailer_TimesOutOnStalledServer(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		// Aceita e não responde, nem o cumprimento inicial.
+		conn, err := ln.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(5 * time.Second)
+		}
+	}()
+
+	mailer, err := mail.NewSMTPMailer(ln.Addr().String(), "", "", "noreply@example.com")
+	require.NoError(t, err)
+	mailer.Timeout = 100 * time.Millisecond
+
+	start := time.Now()
+	err = mailer.Send(usecase.EmailMessage{To: "ana@example.com", Subject: "Oi", Body: "Olá"})
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/usecase"
+	"mime"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeader = errors.New("cabeçalho de e-mail inválido")
+
+// buildMessage monta a mensagem RFC 5322 em texto simples UTF-8. Quebras de
+// linha nos cabeçalhos são recusadas para evitar injeção de cabeçalhos.
+func buildMessage(from string, message usecase.EmailMessage, now time.Time) ([]byte, error) {
+	for _, value := range []string{from, message.To, message.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", message.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", message.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	qp := quotedprintable.NewWriter(&buf)
+	if _, err := qp.Write([]byte(message.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+package mail
+
+import (
+	"crypto/tls"
+	"fmt"
+	"hackaton-service-api/internal/usecase"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTPMailer envia pelo servidor em Addr (host:porta). A conexão usa
+// STARTTLS quando o servidor oferece; a autenticação PLAIN só é feita com
+// Username preenchido e, fora de localhost, exige TLS. Timeout limita a
+// conexão e a conversa inteira com o servidor, para que um SMTP travado não
+// prenda a requisição que pediu o envio.
+type SMTPMailer struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// DefaultSMTPTimeout é o Timeout de NewSMTPMailer.
+const DefaultSMTPTimeout = 30 * time.Second
+
+var _ usecase.Mailer = (*SMTPMailer)(nil)
+
+func NewSMTPMailer(addr, username, password, from string) (*SMTPMailer, error) {
+	if _, err := netmail.ParseAddress(from); err != nil {
+		return nil, fmt.Errorf("remetente inválido: %w", err)
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		return nil, fmt.Errorf("endereço SMTP inválido: %w", err)
+	}
+	return &SMTPMailer{Addr: addr, Username: username, Password: password, From: from, Timeout: DefaultSMTPTimeout}, nil
+}
+
+func (m *SMTPMailer) Send(message usecase.EmailMessage) error {
+	from, err := netmail.ParseAddress(m.From)
+	if err != nil {
+		return err
+	}
+	to, err := netmail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("destinatário inválido: %w", err)
+	}
+
+	body, err := buildMessage(m.From, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	conn, err := (&net.Dialer{Timeout: m.Timeout}).Dial("tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if m.Timeout > 0 {
+		if err := conn.SetDeadline(time.Now().Add(m.Timeout)); err != nil {
+			return err
+		}
+	}
+
+	host, _, _ := net.SplitHostPort(m.Addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	// Mesma sequência do smtp.SendMail, sobre a conexão com prazo.
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
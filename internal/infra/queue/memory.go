@@ -0,0 +1,56 @@
+package queue
+
+import (
+	"context"
+	"hackaton-service-api/internal/entity"
+	"strconv"
+	"sync/atomic"
+)
+
+// MemoryQueue é uma fila em canal, para testes e para rodar API e worker no
+// mesmo processo. Não há redelivery: uma mensagem recebida já saiu da fila.
+type MemoryQueue struct {
+	messages chan *Delivery
+	nextID   atomic.Uint64
+}
+
+var _ Consumer = (*MemoryQueue)(nil)
+
+func NewMemoryQueue(capacity int) *MemoryQueue {
+	return &MemoryQueue{messages: make(chan *Delivery, capacity)}
+}
+
+// SendMessage não bloqueia a requisição: com a fila cheia devolve
+// ErrQueueFull, como um SQS indisponível.
+func (q *MemoryQueue) SendMessage(message entity.VideoMessage) error {
+	delivery := &Delivery{
+		ID:       strconv.FormatUint(q.nextID.Add(1), 10),
+		Message:  message,
+		Attempts: 1,
+	}
+
+	select {
+	case q.messages <- delivery:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+func (q *MemoryQueue) Receive(ctx context.Context) (*Delivery, error) {
+	select {
+	case delivery := <-q.messages:
+		return delivery, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (q *MemoryQueue) Ack(id string) error {
+	return nil
+}
+
+// Len é o número de mensagens aguardando consumo.
+func (q *MemoryQueue) Len() int {
+	return len(q.messages)
+}
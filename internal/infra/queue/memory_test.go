@@ -0,0 +1,36 @@
+package queue_test
+
+import (
+	"context"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/infra/queue"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryQueue(t *testing.T) {
+	q := queue.NewMemoryQueue(1)
+
+	message := entity.NewVideoMessage(&entity.Video{ID: "v1", UserID: "u1", InputKey: "uploads/v.mp4"}, "e@e.com")
+	require.NoError(t, q.SendMessage(message))
+	assert.ErrorIs(t, q.SendMessage(message), queue.ErrQueueFull)
+	assert.Equal(t, 1, q.Len())
+
+	delivery, err := q.Receive(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, message, delivery.Message)
+	assert.NoError(t, q.Ack(delivery.ID))
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestMemoryQueue_ReceiveRespectsContext(t *testing.T) {
+	q := queue.NewMemoryQueue(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := q.Receive(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
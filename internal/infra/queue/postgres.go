@@ -0,0 +1,113 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"hackaton-service-api/internal/entity"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Job é uma mensagem na tabela queue_jobs. AvailableAt controla tanto o
+// atraso inicial quanto o visibility timeout após uma entrega.
+type Job struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement"`
+	Queue       string    `gorm:"not null;index:idx_queue_jobs_available,priority:1"`
+	Payload     string    `gorm:"type:jsonb;not null"`
+	Attempts    int       `gorm:"not null;default:0"`
+	AvailableAt time.Time `gorm:"not null;index:idx_queue_jobs_available,priority:2"`
+	CreatedAt   time.Time
+}
+
+func (Job) TableName() string { return "queue_jobs" }
+
+// PostgresQueue é uma fila de jobs no próprio banco: os consumidores disputam
+// as linhas com FOR UPDATE SKIP LOCKED, sem que dois recebam o mesmo job.
+type PostgresQueue struct {
+	DB                *gorm.DB
+	Name              string
+	VisibilityTimeout time.Duration
+	PollInterval      time.Duration
+}
+
+var _ Consumer = (*PostgresQueue)(nil)
+
+func NewPostgresQueue(db *gorm.DB, name string) *PostgresQueue {
+	return &PostgresQueue{
+		DB:                db,
+		Name:              name,
+		VisibilityTimeout: 5 * time.Minute,
+		PollInterval:      time.Second,
+	}
+}
+
+// Migrate cria a tabela de jobs; só é chamado quando o backend está em uso.
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&Job{})
+}
+
+func (q *PostgresQueue) SendMessage(message entity.VideoMessage) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	return q.DB.Create(&Job{
+		Queue:       q.Name,
+		Payload:     string(payload),
+		AvailableAt: time.Now(),
+	}).Error
+}
+
+// Receive espera até haver um job disponível. O job fica invisível pelo
+// VisibilityTimeout; se o consumidor cair antes do Ack, é entregue de novo.
+func (q *PostgresQueue) Receive(ctx context.Context) (*Delivery, error) {
+	for {
+		delivery, err := q.claim()
+		if err != nil || delivery != nil {
+			return delivery, err
+		}
+
+		select {
+		case <-time.After(q.PollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (q *PostgresQueue) claim() (*Delivery, error) {
+	now := time.Now()
+
+	var jobs []Job
+	err := q.DB.Raw(`UPDATE queue_jobs SET attempts = attempts + 1, available_at = ?
+		WHERE id = (
+			SELECT id FROM queue_jobs
+			WHERE queue = ? AND available_at <= ?
+			ORDER BY available_at, id
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING *`, now.Add(q.VisibilityTimeout), q.Name, now).Scan(&jobs).Error
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+
+	job := jobs[0]
+	var message entity.VideoMessage
+	if err := json.Unmarshal([]byte(job.Payload), &message); err != nil {
+		return nil, err
+	}
+
+	return &Delivery{
+		ID:       strconv.FormatInt(job.ID, 10),
+		Message:  message,
+		Attempts: job.Attempts,
+	}, nil
+}
+
+func (q *PostgresQueue) Ack(id string) error {
+	return q.DB.Where("id = ? AND queue = ?", id, q.Name).Delete(&Job{}).Error
+}
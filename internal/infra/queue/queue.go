@@ -0,0 +1,23 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"hackaton-service-api/internal/entity"
+)
+
+var ErrQueueFull = errors.New("fila cheia")
+
+// Delivery é uma mensagem recebida. Ela só sai da fila com Ack; no backend
+// Postgres, sem Ack ela volta a ser entregue após o visibility timeout.
+type Delivery struct {
+	ID       string
+	Message  entity.VideoMessage
+	Attempts int
+}
+
+// Consumer é o lado do worker, usado no modo de binário único e nos testes.
+type Consumer interface {
+	Receive(ctx context.Context) (*Delivery, error)
+	Ack(id string) error
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"hackaton-service-api/internal/auth"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
 )
@@ -30,4 +31,17 @@ func GetDatabaseSecrets(f *AWSClientFactory, secretName string) (*DbCredentials,
 	var creds DbCredentials
 	err = json.Unmarshal([]byte(*result.SecretString), &creds)
 	return &creds, err
+}
+
+// GetJWTKeys lê do Secrets Manager o mesmo JSON aceito em JWT_KEYS.
+func GetJWTKeys(f *AWSClientFactory, secretName string) (*auth.KeySetConfig, error) {
+	client := secretsmanager.NewFromConfig(f.Config)
+	result, err := client.GetSecretValue(context.TODO(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretName),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return auth.ParseKeySetConfig([]byte(aws.ToString(result.SecretString)))
 }
\ No newline at end of file
@@ -3,13 +3,22 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"io"
+	"mime"
 	"mime/multipart"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 )
 
 type StorageService struct {
@@ -17,19 +26,37 @@ type StorageService struct {
 	SQSClient *sqs.Client
 	Bucket    string
 	QueueURL  string
+	Presign   PresignPolicy
 }
 
-type SQSMessage struct {
-	VideoID string `json:"video_id"`
-	Email   string `json:"email"`
+// PresignPolicy controla os links pré-assinados de download e de upload
+// direto.
+type PresignPolicy struct {
+	Expiry time.Duration
+	// PublicEndpoint é o endereço do S3 que o cliente alcança, quando difere
+	// do usado pela API (ex.: localstack no docker compose). A assinatura é
+	// calculada para ele, então o link não precisa ser reescrito.
+	PublicEndpoint string
+	// Disposition é o tipo do response-content-disposition dos downloads
+	// (attachment ou inline), com o nome do arquivo; vazio não o envia.
+	Disposition string
 }
 
+func DefaultPresignPolicy() PresignPolicy {
+	return PresignPolicy{Expiry: 15 * time.Minute, Disposition: "attachment"}
+}
+
+// SQSMessage mantém o nome usado pelo worker; o formato é compartilhado com
+// os outros backends de fila.
+type SQSMessage = entity.VideoMessage
+
 func NewStorageService(s3Client *s3.Client, sqsClient *sqs.Client, bucket, queueURL string) *StorageService {
 	return &StorageService{
 		S3Client:  s3Client,
 		SQSClient: sqsClient,
 		Bucket:    bucket,
 		QueueURL:  queueURL,
+		Presign:   DefaultPresignPolicy(),
 	}
 }
 
@@ -42,47 +69,265 @@ func (s *StorageService) UploadFile(file multipart.File, filename string) error
 	return err
 }
 
-func (s *StorageService) SendMessage(videoID, email string) error {
-	payload := SQSMessage{
-		VideoID: videoID,
-		Email:   email,
-	}
-
-	body, err := json.Marshal(payload)
+func (s *StorageService) SendMessage(message SQSMessage) error {
+	body, err := json.Marshal(message)
 	if err != nil {
 		return err
 	}
 
+	attributes := make(map[string]sqstypes.MessageAttributeValue)
+	for name, value := range message.Attributes() {
+		if value == "" {
+			// O SQS rejeita atributos vazios.
+			continue
+		}
+		attributes[name] = sqstypes.MessageAttributeValue{
+			DataType:    aws.String("String"),
+			StringValue: aws.String(value),
+		}
+	}
+
 	_, err = s.SQSClient.SendMessage(context.TODO(), &sqs.SendMessageInput{
-		QueueUrl:    aws.String(s.QueueURL),
-		MessageBody: aws.String(string(body)),
+		QueueUrl:          aws.String(s.QueueURL),
+		MessageBody:       aws.String(string(body)),
+		MessageAttributes: attributes,
+	})
+	return err
+}
+
+// GeneratePresignedURL gera o link de download. Com fileName, o S3 responde
+// com Content-Disposition para o navegador salvar com esse nome.
+func (s *StorageService) GeneratePresignedURL(key, fileName string) (string, error) {
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	}
+	if fileName != "" && s.Presign.Disposition != "" {
+		input.ResponseContentDisposition = aws.String(mime.FormatMediaType(s.Presign.Disposition, map[string]string{"filename": fileName}))
+	}
+
+	req, err := s.presignClient().PresignGetObject(context.TODO(), input, s.presignOptions)
+	if err != nil {
+		return "", err
+	}
+
+	return req.URL, nil
+}
+
+func (s *StorageService) GeneratePresignedUploadURL(key, contentType string, size int64) (string, error) {
+	req, err := s.presignClient().PresignPutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		ContentType:   aws.String(contentType),
+		ContentLength: aws.Int64(size),
+	}, s.presignOptions)
+	if err != nil {
+		return "", err
+	}
+
+	return req.URL, nil
+}
+
+func (s *StorageService) presignClient() *s3.PresignClient {
+	return s3.NewPresignClient(s.S3Client, func(opts *s3.PresignOptions) {
+		if s.Presign.PublicEndpoint == "" {
+			return
+		}
+		opts.ClientOptions = append(opts.ClientOptions, func(o *s3.Options) {
+			// O resolvedor configurado na fábrica tem precedência sobre o
+			// BaseEndpoint.
+			o.EndpointResolver = nil
+			o.BaseEndpoint = aws.String(s.Presign.PublicEndpoint)
+		})
+	})
+}
+
+func (s *StorageService) presignOptions(opts *s3.PresignOptions) {
+	opts.Expires = s.Presign.Expiry
+}
+
+func (s *StorageService) ObjectExists(key string) (bool, error) {
+	_, err := s.S3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *StorageService) ObjectSize(key string) (int64, error) {
+	head, err := s.S3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return aws.ToInt64(head.ContentLength), nil
+}
+
+// ReadObject abre o objeto inteiro em um único GET, para leitura sequencial.
+func (s *StorageService) ReadObject(key string) (io.ReadCloser, error) {
+	out, err := s.S3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+// DeleteObject é idempotente: o S3 não devolve erro para chave inexistente.
+func (s *StorageService) DeleteObject(key string) error {
+	_, err := s.S3Client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// CopyObject copia dentro do bucket, sem passar o conteúdo pela API. Uma
+// única cópia aceita objetos de até 5 GB, o mesmo limite do PUT pré-assinado.
+func (s *StorageService) CopyObject(srcKey, dstKey string) error {
+	_, err := s.S3Client.CopyObject(context.TODO(), &s3.CopyObjectInput{
+		Bucket:     aws.String(s.Bucket),
+		Key:        aws.String(dstKey),
+		CopySource: aws.String(copySource(s.Bucket, srcKey)),
 	})
 	return err
 }
 
-func (s *StorageService) GeneratePresignedURL(key string) (string, error) {
-    presignClient := s3.NewPresignClient(s.S3Client)
-    
-    req, err := presignClient.PresignGetObject(context.TODO(), &s3.GetObjectInput{
-        Bucket: aws.String(s.Bucket),
-        Key:    aws.String(key),
-    }, func(opts *s3.PresignOptions) {
-        opts.Expires = 15 * time.Minute
-    })
+// copySource monta o x-amz-copy-source, que exige a chave codificada como
+// URL; as barras continuam separando os segmentos.
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
+
+// ReadObjectHeader lê só os primeiros n bytes do objeto (GET com Range).
+func (s *StorageService) ReadObjectHeader(key string, n int64) ([]byte, error) {
+	out, err := s.S3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=0-%d", n-1)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	return io.ReadAll(io.LimitReader(out.Body, n))
+}
+
+// OpenObject devolve um io.ReaderAt em que cada leitura é um GET com Range,
+// para inspecionar partes do objeto sem baixá-lo inteiro.
+func (s *StorageService) OpenObject(key string) (io.ReaderAt, int64, error) {
+	head, err := s.S3Client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return &objectReader{storage: s, key: key}, aws.ToInt64(head.ContentLength), nil
+}
+
+type objectReader struct {
+	storage *StorageService
+	key     string
+}
 
-    if err != nil {
-        return "", err
-    }
+func (r *objectReader) ReadAt(p []byte, off int64) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
 
-	//return req.URL, nil
-	// força urlStr para rodar no docker compose
-	urlStr := req.URL
-	urlStr = strings.Replace(urlStr, "http://localstack:4566", "http://localhost:4566", 1)
+	out, err := r.storage.S3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(r.storage.Bucket),
+		Key:    aws.String(r.key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", off, off+int64(len(p))-1)),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer out.Body.Close()
 
-    return urlStr, nil
-	
+	n, err := io.ReadFull(out.Body, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+	return n, err
 }
 
 func (s *StorageService) GetBucketName() string {
     return s.Bucket
+}
+
+func (s *StorageService) CreateMultipartUpload(key string) (string, error) {
+	out, err := s.S3Client.CreateMultipartUpload(context.TODO(), &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(s.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.UploadId), nil
+}
+
+// UploadPart repassa o corpo da requisição sem bufferizar. Um stream não
+// permite calcular o SHA-256 nem o checksum antes do envio, então a parte vai
+// com UNSIGNED-PAYLOAD e o S3 confere a integridade pelo Content-Length.
+func (s *StorageService) UploadPart(key, uploadID string, partNumber int32, body io.Reader, size int64) (string, error) {
+	out, err := s.S3Client.UploadPart(context.TODO(), &s3.UploadPartInput{
+		Bucket:        aws.String(s.Bucket),
+		Key:           aws.String(key),
+		UploadId:      aws.String(uploadID),
+		PartNumber:    aws.Int32(partNumber),
+		Body:          body,
+		ContentLength: aws.Int64(size),
+	}, func(o *s3.Options) {
+		o.RequestChecksumCalculation = aws.RequestChecksumCalculationWhenRequired
+		o.APIOptions = append(o.APIOptions, v4.SwapComputePayloadSHA256ForUnsignedPayloadMiddleware)
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.ETag), nil
+}
+
+func (s *StorageService) CompleteMultipartUpload(key, uploadID string, parts []entity.UploadPart) error {
+	completed := make([]types.CompletedPart, 0, len(parts))
+	for _, p := range parts {
+		completed = append(completed, types.CompletedPart{
+			ETag:       aws.String(p.ETag),
+			PartNumber: aws.Int32(p.PartNumber),
+		})
+	}
+
+	_, err := s.S3Client.CompleteMultipartUpload(context.TODO(), &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(s.Bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uploadID),
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	return err
+}
+
+func (s *StorageService) AbortMultipartUpload(key, uploadID string) error {
+	_, err := s.S3Client.AbortMultipartUpload(context.TODO(), &s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(s.Bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uploadID),
+	})
+	return err
 }
\ No newline at end of file
@@ -2,16 +2,29 @@ package service
 
 import "hackaton-service-api/internal/auth"
 
-type TokenService struct{}
+type TokenService struct {
+	Keys *auth.KeySet
+}
+
+func NewTokenService(keys *auth.KeySet) *TokenService {
+	return &TokenService{Keys: keys}
+}
 
-func NewTokenService() *TokenService {
-	return &TokenService{}
+func (s *TokenService) GenerateToken(userID, sessionID string) (string, error) {
+	return auth.GenerateToken(s.Keys, userID, sessionID)
 }
 
-func (s *TokenService) GenerateToken(userID string) (string, error) {
-	return auth.GenerateToken(userID)
+func (s *TokenService) ValidateToken(token string) (string, string, error) {
+	return auth.ValidateToken(s.Keys, token)
 }
 
-func (s *TokenService) ValidateToken(token string) (string, error) {
-	return auth.ValidateToken(token)
-}
\ No newline at end of file
+func (s *TokenService) JWKS() auth.JWKS {
+	return s.Keys.JWKS()
+}
+func (s *TokenService) GenerateVerificationToken(userID, email string) (string, error) {
+	return auth.GenerateEmailVerificationToken(s.Keys, userID, email)
+}
+
+func (s *TokenService) ValidateVerificationToken(token string) (string, string, error) {
+	return auth.ValidateEmailVerificationToken(s.Keys, token)
+}
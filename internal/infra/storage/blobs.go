@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound   = errors.New("objeto não encontrado")
+	ErrInvalidKey = errors.New("chave de objeto inválida")
+)
+
+// Object é um objeto aberto para leitura. Quem abre deve fechar.
+type Object interface {
+	io.ReadSeeker
+	io.ReaderAt
+	io.Closer
+}
+
+// Blobs é o armazenamento bruto por chave sobre o qual Service implementa as
+// operações de vídeo. As chaves usam "/" como separador, como no S3.
+type Blobs interface {
+	// Put grava o objeto inteiro, substituindo o anterior se existir.
+	Put(key string, r io.Reader) (int64, error)
+	Open(key string) (Object, int64, error)
+	// Delete não falha se o objeto não existir.
+	Delete(key string) error
+	// DeleteAll remove todos os objetos sob o prefixo, que termina em "/".
+	DeleteAll(prefix string) error
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// LocalBlobs guarda cada objeto como um arquivo sob Root, espelhando as
+// pastas da chave (uploads/..., outputs/...).
+type LocalBlobs struct {
+	Root string
+}
+
+var _ Blobs = (*LocalBlobs)(nil)
+
+func NewLocalBlobs(root string) (*LocalBlobs, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalBlobs{Root: root}, nil
+}
+
+// path rejeita chaves que sairiam de Root (ex.: "../", caminhos absolutos),
+// já que parte da chave vem do nome de arquivo enviado pelo usuário.
+func (b *LocalBlobs) path(key string) (string, error) {
+	name := filepath.FromSlash(key)
+	if !filepath.IsLocal(name) {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(b.Root, name), nil
+}
+
+// Put escreve num arquivo temporário e renomeia, para que leituras
+// simultâneas nunca vejam um objeto pela metade.
+func (b *LocalBlobs) Put(key string, r io.Reader) (int64, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return 0, err
+	}
+	return n, os.Rename(tmp.Name(), path)
+}
+
+func (b *LocalBlobs) Open(key string) (Object, int64, error) {
+	path, err := b.path(key)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, ErrNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	if info.IsDir() {
+		file.Close()
+		return nil, 0, ErrNotFound
+	}
+	return file, info.Size(), nil
+}
+
+func (b *LocalBlobs) Delete(key string) error {
+	path, err := b.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (b *LocalBlobs) DeleteAll(prefix string) error {
+	path, err := b.path(prefix)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(path)
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+// MemoryBlobs guarda os objetos em memória; serve para testes e para rodar a
+// API sem nenhuma dependência externa. Tudo se perde ao reiniciar.
+type MemoryBlobs struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+}
+
+var _ Blobs = (*MemoryBlobs)(nil)
+
+func NewMemoryBlobs() *MemoryBlobs {
+	return &MemoryBlobs{objects: make(map[string][]byte)}
+}
+
+func (b *MemoryBlobs) Put(key string, r io.Reader) (int64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.objects[key] = data
+	return int64(len(data)), nil
+}
+
+func (b *MemoryBlobs) Open(key string) (Object, int64, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	data, ok := b.objects[key]
+	if !ok {
+		return nil, 0, ErrNotFound
+	}
+	// Put sempre troca o slice inteiro, então o leitor não vê escritas parciais.
+	return memoryObject{bytes.NewReader(data)}, int64(len(data)), nil
+}
+
+func (b *MemoryBlobs) Delete(key string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.objects, key)
+	return nil
+}
+
+func (b *MemoryBlobs) DeleteAll(prefix string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for key := range b.objects {
+		if strings.HasPrefix(key, prefix) {
+			delete(b.objects, key)
+		}
+	}
+	return nil
+}
+
+type memoryObject struct{ *bytes.Reader }
+
+func (memoryObject) Close() error { return nil }
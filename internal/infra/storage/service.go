@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+var ErrSizeMismatch = errors.New("tamanho enviado difere do informado")
+
+// DispositionParam é o parâmetro do link de download com o
+// Content-Disposition da resposta, com o mesmo nome usado pelo S3.
+const DispositionParam = "response-content-disposition"
+
+// As partes de um upload multipart ficam sob este prefixo até o complete.
+const multipartPrefix = ".multipart/"
+
+// Service implementa os contratos de armazenamento dos use cases sobre
+// Blobs, sem AWS: os downloads e uploads diretos usam links assinados
+// servidos pela própria API (rotas em PathPrefix).
+type Service struct {
+	Blobs  Blobs
+	Bucket string
+	Signer URLSigner
+	// Disposition tem o mesmo papel que em service.PresignPolicy.
+	Disposition string
+}
+
+func NewService(blobs Blobs, bucket string, signer URLSigner) *Service {
+	return &Service{Blobs: blobs, Bucket: bucket, Signer: signer}
+}
+
+func (s *Service) UploadFile(file multipart.File, filename string) error {
+	_, err := s.Blobs.Put("uploads/"+filename, file)
+	return err
+}
+
+// GeneratePresignedURL repete o response-content-disposition do S3. Ele fica
+// fora da assinatura: o link já dá acesso ao objeto e o parâmetro só muda o
+// nome com que o navegador o salva.
+func (s *Service) GeneratePresignedURL(key, fileName string) (string, error) {
+	link := s.Signer.URL(http.MethodGet, key, "", 0)
+	if fileName != "" && s.Disposition != "" {
+		disposition := mime.FormatMediaType(s.Disposition, map[string]string{"filename": fileName})
+		link += "&" + url.Values{DispositionParam: {disposition}}.Encode()
+	}
+	return link, nil
+}
+
+func (s *Service) GeneratePresignedUploadURL(key, contentType string, size int64) (string, error) {
+	return s.Signer.URL(http.MethodPut, key, contentType, size), nil
+}
+
+func (s *Service) DeleteObject(key string) error {
+	return s.Blobs.Delete(key)
+}
+
+func (s *Service) ObjectExists(key string) (bool, error) {
+	obj, _, err := s.Blobs.Open(key)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, obj.Close()
+}
+
+func (s *Service) ObjectSize(key string) (int64, error) {
+	obj, size, err := s.Blobs.Open(key)
+	if err != nil {
+		return 0, err
+	}
+	return size, obj.Close()
+}
+
+func (s *Service) ReadObject(key string) (io.ReadCloser, error) {
+	obj, _, err := s.Blobs.Open(key)
+	return obj, err
+}
+
+func (s *Service) CopyObject(srcKey, dstKey string) error {
+	obj, _, err := s.Blobs.Open(srcKey)
+	if err != nil {
+		return err
+	}
+	defer obj.Close()
+
+	_, err = s.Blobs.Put(dstKey, obj)
+	return err
+}
+
+func (s *Service) ReadObjectHeader(key string, n int64) ([]byte, error) {
+	obj, _, err := s.Blobs.Open(key)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+
+	return io.ReadAll(io.LimitReader(obj, n))
+}
+
+// OpenObject não mantém o objeto aberto: cada ReadAt abre e fecha, já que
+// quem chama não tem como liberar o recurso.
+func (s *Service) OpenObject(key string) (io.ReaderAt, int64, error) {
+	obj, size, err := s.Blobs.Open(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	obj.Close()
+	return blobReader{blobs: s.Blobs, key: key}, size, nil
+}
+
+func (s *Service) GetBucketName() string {
+	return s.Bucket
+}
+
+func (s *Service) CreateMultipartUpload(key string) (string, error) {
+	return uuid.New().String(), nil
+}
+
+// UploadPart descarta a parte cujo corpo não tem exatamente size bytes, como
+// o S3 faz com o Content-Length.
+func (s *Service) UploadPart(key, uploadID string, partNumber int32, body io.Reader, size int64) (string, error) {
+	hash := md5.New()
+	n, err := s.Blobs.Put(partKey(uploadID, partNumber), io.TeeReader(io.LimitReader(body, size+1), hash))
+	if err != nil {
+		return "", err
+	}
+	if n != size {
+		s.Blobs.Delete(partKey(uploadID, partNumber))
+		return "", ErrSizeMismatch
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// CompleteMultipartUpload concatena as partes na ordem do número, como o S3.
+func (s *Service) CompleteMultipartUpload(key, uploadID string, parts []entity.UploadPart) error {
+	ordered := slices.Clone(parts)
+	slices.SortFunc(ordered, func(a, b entity.UploadPart) int { return int(a.PartNumber - b.PartNumber) })
+
+	readers := make([]io.Reader, 0, len(ordered))
+	for _, part := range ordered {
+		obj, _, err := s.Blobs.Open(partKey(uploadID, part.PartNumber))
+		if err != nil {
+			return fmt.Errorf("parte %d: %w", part.PartNumber, err)
+		}
+		defer obj.Close()
+		readers = append(readers, obj)
+	}
+
+	if _, err := s.Blobs.Put(key, io.MultiReader(readers...)); err != nil {
+		return err
+	}
+	return s.Blobs.DeleteAll(multipartPrefix + uploadID + "/")
+}
+
+func (s *Service) AbortMultipartUpload(key, uploadID string) error {
+	return s.Blobs.DeleteAll(multipartPrefix + uploadID + "/")
+}
+
+// OpenSigned valida o link de download antes de abrir o objeto.
+func (s *Service) OpenSigned(key string, query url.Values) (Object, error) {
+	if err := s.Signer.Verify(http.MethodGet, key, query, "", 0); err != nil {
+		return nil, err
+	}
+	obj, _, err := s.Blobs.Open(key)
+	return obj, err
+}
+
+// PutSigned grava o corpo de um upload direto. O link fixa Content-Type e
+// tamanho; um corpo maior ou menor que o assinado é descartado.
+func (s *Service) PutSigned(key string, query url.Values, contentType string, size int64, body io.Reader) error {
+	if err := s.Signer.Verify(http.MethodPut, key, query, contentType, size); err != nil {
+		return err
+	}
+
+	n, err := s.Blobs.Put(key, io.LimitReader(body, size+1))
+	if err != nil {
+		return err
+	}
+	if n != size {
+		s.Blobs.Delete(key)
+		return ErrSizeMismatch
+	}
+	return nil
+}
+
+func partKey(uploadID string, partNumber int32) string {
+	return fmt.Sprintf("%s%s/%05d", multipartPrefix, uploadID, partNumber)
+}
+
+type blobReader struct {
+	blobs Blobs
+	key   string
+}
+
+func (r blobReader) ReadAt(p []byte, off int64) (int, error) {
+	obj, _, err := r.blobs.Open(r.key)
+	if err != nil {
+		return 0, err
+	}
+	defer obj.Close()
+	return obj.ReadAt(p, off)
+}
@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PathPrefix é a rota da API que serve os links assinados.
+const PathPrefix = "/storage/"
+
+var ErrInvalidSignature = errors.New("link inválido ou expirado")
+
+// URLSigner gera links com validade, no lugar das URLs pré-assinadas do S3.
+// A assinatura cobre método, chave e validade; nos PUTs cobre também o
+// Content-Type e o tamanho, como o S3 faz.
+type URLSigner struct {
+	Secret  []byte
+	BaseURL string
+	TTL     time.Duration
+}
+
+func (s URLSigner) URL(method, key, contentType string, size int64) string {
+	expires := strconv.FormatInt(time.Now().Add(s.TTL).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(method, key, expires, contentType, size))
+
+	return strings.TrimSuffix(s.BaseURL, "/") + PathPrefix + escapeKey(key) + "?" + query.Encode()
+}
+
+func (s URLSigner) Verify(method, key string, query url.Values, contentType string, size int64) error {
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(s.sign(method, key, expires, contentType, size))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func (s URLSigner) sign(method, key, expires, contentType string, size int64) string {
+	mac := hmac.New(sha256.New, s.Secret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%s\n%d", method, key, expires, contentType, size)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,199 @@
+package storage_test
+
+import (
+	"bytes"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/infra/storage"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newService(blobs storage.Blobs, ttl time.Duration) *storage.Service {
+	return storage.NewService(blobs, "local", storage.URLSigner{Secret: []byte("segredo"), BaseURL: "http://api", TTL: ttl})
+}
+
+// signedRequest separa a chave e a query de um link gerado pelo Service.
+func signedRequest(t *testing.T, link string) (string, url.Values) {
+	parsed, err := url.Parse(link)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(parsed.Path, storage.PathPrefix))
+	return strings.TrimPrefix(parsed.Path, storage.PathPrefix), parsed.Query()
+}
+
+func TestService_Objects(t *testing.T) {
+	svc := newService(storage.NewMemoryBlobs(), time.Minute)
+	_, err := svc.Blobs.Put("uploads/v.mp4", strings.NewReader("ftyp-conteudo-video"))
+	require.NoError(t, err)
+
+	exists, err := svc.ObjectExists("uploads/v.mp4")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	header, err := svc.ReadObjectHeader("uploads/v.mp4", 4)
+	require.NoError(t, err)
+	assert.Equal(t, "ftyp", string(header))
+
+	r, size, err := svc.OpenObject("uploads/v.mp4")
+	require.NoError(t, err)
+	buf := make([]byte, 5)
+	_, err = r.ReadAt(buf, size-5)
+	require.NoError(t, err)
+	assert.Equal(t, "video", string(buf))
+
+	objectSize, err := svc.ObjectSize("uploads/v.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, size, objectSize)
+
+	body, err := svc.ReadObject("uploads/v.mp4")
+	require.NoError(t, err)
+	content, err := io.ReadAll(body)
+	require.NoError(t, err)
+	require.NoError(t, body.Close())
+	assert.Equal(t, "ftyp-conteudo-video", string(content))
+
+	require.NoError(t, svc.CopyObject("uploads/v.mp4", "uploads/copia.mp4"))
+	_, err = svc.Blobs.Put("uploads/v.mp4", strings.NewReader("substituído"))
+	require.NoError(t, err)
+	header, err = svc.ReadObjectHeader("uploads/copia.mp4", 100)
+	require.NoError(t, err)
+	assert.Equal(t, "ftyp-conteudo-video", string(header))
+	assert.ErrorIs(t, svc.CopyObject("uploads/nada.mp4", "uploads/x.mp4"), storage.ErrNotFound)
+
+	require.NoError(t, svc.DeleteObject("uploads/v.mp4"))
+	exists, err = svc.ObjectExists("uploads/v.mp4")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestService_Multipart(t *testing.T) {
+	blobs := storage.NewMemoryBlobs()
+	svc := newService(blobs, time.Minute)
+
+	uploadID, err := svc.CreateMultipartUpload("uploads/v.mp4")
+	require.NoError(t, err)
+
+	// Partes fora de ordem, como acontece em uploads paralelos.
+	etag2, err := svc.UploadPart("uploads/v.mp4", uploadID, 2, strings.NewReader("mundo"), 5)
+	require.NoError(t, err)
+	etag1, err := svc.UploadPart("uploads/v.mp4", uploadID, 1, strings.NewReader("olá "), 5)
+	require.NoError(t, err)
+	assert.NotEqual(t, etag1, etag2)
+
+	_, err = svc.UploadPart("uploads/v.mp4", uploadID, 3, strings.NewReader("curta"), 10)
+	assert.ErrorIs(t, err, storage.ErrSizeMismatch)
+	_, _, err = blobs.Open(".multipart/" + uploadID + "/00003")
+	assert.ErrorIs(t, err, storage.ErrNotFound, "parte incompleta é descartada")
+
+	parts := []entity.UploadPart{{PartNumber: 2, ETag: etag2}, {PartNumber: 1, ETag: etag1}}
+	require.NoError(t, svc.CompleteMultipartUpload("uploads/v.mp4", uploadID, parts))
+
+	obj, _, err := blobs.Open("uploads/v.mp4")
+	require.NoError(t, err)
+	content, _ := io.ReadAll(obj)
+	assert.Equal(t, "olá mundo", string(content))
+
+	_, _, err = blobs.Open(".multipart/" + uploadID + "/00001")
+	assert.ErrorIs(t, err, storage.ErrNotFound, "partes são removidas após o complete")
+}
+
+func TestService_SignedDownload(t *testing.T) {
+	svc := newService(storage.NewMemoryBlobs(), time.Minute)
+	svc.Blobs.Put("outputs/frames 1.zip", strings.NewReader("zip"))
+
+	link, err := svc.GeneratePresignedURL("outputs/frames 1.zip", "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link, "http://api/storage/outputs/frames%201.zip?"))
+
+	key, query := signedRequest(t, link)
+	key, _ = url.PathUnescape(key)
+
+	obj, err := svc.OpenSigned(key, query)
+	require.NoError(t, err)
+	obj.Close()
+
+	_, err = svc.OpenSigned("outputs/outro.zip", query)
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature, "assinatura não vale para outra chave")
+
+	query.Set("expires", "9999999999")
+	_, err = svc.OpenSigned(key, query)
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature, "validade faz parte da assinatura")
+}
+
+func TestService_SignedDownloadDisposition(t *testing.T) {
+	svc := newService(storage.NewMemoryBlobs(), time.Minute)
+	svc.Disposition = "attachment"
+	svc.Blobs.Put("outputs/f.zip", strings.NewReader("zip"))
+
+	link, err := svc.GeneratePresignedURL("outputs/f.zip", "aula 1.zip")
+	require.NoError(t, err)
+
+	key, query := signedRequest(t, link)
+	assert.Equal(t, `attachment; filename="aula 1.zip"`, query.Get(storage.DispositionParam))
+
+	obj, err := svc.OpenSigned(key, query)
+	require.NoError(t, err, "o parâmetro não invalida a assinatura")
+	obj.Close()
+}
+
+func TestService_ExpiredLink(t *testing.T) {
+	svc := newService(storage.NewMemoryBlobs(), -time.Minute)
+	svc.Blobs.Put("outputs/f.zip", strings.NewReader("zip"))
+
+	link, _ := svc.GeneratePresignedURL("outputs/f.zip", "")
+	key, query := signedRequest(t, link)
+
+	_, err := svc.OpenSigned(key, query)
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature)
+}
+
+func TestService_SignedUpload(t *testing.T) {
+	svc := newService(storage.NewMemoryBlobs(), time.Minute)
+	body := []byte("0123456789")
+
+	link, err := svc.GeneratePresignedUploadURL("uploads/v.mp4", "video/mp4", int64(len(body)))
+	require.NoError(t, err)
+	key, query := signedRequest(t, link)
+
+	err = svc.PutSigned(key, query, "application/pdf", int64(len(body)), bytes.NewReader(body))
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature, "Content-Type diferente do assinado")
+
+	err = svc.PutSigned(key, query, "video/mp4", 20, bytes.NewReader(append(body, body...)))
+	assert.ErrorIs(t, err, storage.ErrInvalidSignature, "tamanho diferente do assinado")
+
+	err = svc.PutSigned(key, query, "video/mp4", int64(len(body)), bytes.NewReader(body[:4]))
+	assert.ErrorIs(t, err, storage.ErrSizeMismatch)
+
+	require.NoError(t, svc.PutSigned(key, query, "video/mp4", int64(len(body)), bytes.NewReader(body)))
+	exists, _ := svc.ObjectExists("uploads/v.mp4")
+	assert.True(t, exists)
+}
+
+func TestLocalBlobs(t *testing.T) {
+	blobs, err := storage.NewLocalBlobs(t.TempDir())
+	require.NoError(t, err)
+
+	n, err := blobs.Put("uploads/a/v.mp4", strings.NewReader("vídeo"))
+	require.NoError(t, err)
+
+	obj, size, err := blobs.Open("uploads/a/v.mp4")
+	require.NoError(t, err)
+	assert.Equal(t, n, size)
+	obj.Close()
+
+	require.NoError(t, blobs.DeleteAll("uploads/a/"))
+	_, _, err = blobs.Open("uploads/a/v.mp4")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	require.NoError(t, blobs.Delete("uploads/inexistente.mp4"))
+
+	for _, key := range []string{"../fora.mp4", "uploads/../../fora.mp4", "/etc/passwd"} {
+		_, err := blobs.Put(key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
@@ -0,0 +1,202 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Info e Tracks ficam no início do segmento; não é preciso ler além disso.
+const mkvScanSize = 4 << 20
+
+const (
+	idEBML          = 0x1A45DFA3
+	idSegment       = 0x18538067
+	idInfo          = 0x1549A966
+	idTimecodeScale = 0x2AD7B1
+	idDuration      = 0x4489
+	idTracks        = 0x1654AE6B
+	idTrackEntry    = 0xAE
+	idTrackType     = 0x83
+	idCodecID       = 0x86
+	idVideo         = 0xE0
+	idPixelWidth    = 0xB0
+	idPixelHeight   = 0xBA
+	idCluster       = 0x1F43B675
+)
+
+const mkvTrackTypeVideo = 1
+
+var mkvCodecs = map[string]string{
+	"V_MPEG4/ISO/AVC":  "h264",
+	"V_MPEGH/ISO/HEVC": "hevc",
+	"V_AV1":            "av1",
+	"V_VP8":            "vp8",
+	"V_VP9":            "vp9",
+	"V_MPEG4/ISO/ASP":  "mpeg4",
+}
+
+func probeMKV(r io.ReaderAt, size int64) (*Metadata, error) {
+	buf := make([]byte, min(size, mkvScanSize))
+	n, err := r.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	buf = buf[:n]
+
+	var segment []byte
+	eachElement(buf, func(id uint64, body []byte) bool {
+		if id == idSegment {
+			segment = body
+			return false
+		}
+		return true
+	})
+	if segment == nil {
+		return nil, fmt.Errorf("%w: segmento Matroska não encontrado", ErrMalformed)
+	}
+
+	meta := &Metadata{}
+	timecodeScale := uint64(time.Millisecond)
+	var duration float64
+
+	eachElement(segment, func(id uint64, body []byte) bool {
+		switch id {
+		case idInfo:
+			eachElement(body, func(id uint64, value []byte) bool {
+				switch id {
+				case idTimecodeScale:
+					timecodeScale = readUint(value)
+				case idDuration:
+					duration = readFloat(value)
+				}
+				return true
+			})
+		case idTracks:
+			eachElement(body, func(id uint64, entry []byte) bool {
+				if id == idTrackEntry && meta.VideoCodec == "" {
+					parseTrackEntry(entry, meta)
+				}
+				return true
+			})
+		case idCluster:
+			return false
+		}
+		return true
+	})
+
+	meta.Duration = time.Duration(duration * float64(timecodeScale))
+	return meta, nil
+}
+
+func parseTrackEntry(entry []byte, meta *Metadata) {
+	var trackType uint64
+	var codec string
+	var width, height uint64
+
+	eachElement(entry, func(id uint64, value []byte) bool {
+		switch id {
+		case idTrackType:
+			trackType = readUint(value)
+		case idCodecID:
+			codec = string(value)
+		case idVideo:
+			eachElement(value, func(id uint64, v []byte) bool {
+				switch id {
+				case idPixelWidth:
+					width = readUint(v)
+				case idPixelHeight:
+					height = readUint(v)
+				}
+				return true
+			})
+		}
+		return true
+	})
+
+	if trackType != mkvTrackTypeVideo {
+		return
+	}
+	meta.VideoCodec = codec
+	if mapped, ok := mkvCodecs[codec]; ok {
+		meta.VideoCodec = mapped
+	}
+	meta.Width, meta.Height = int(width), int(height)
+}
+
+// eachElement itera os elementos EBML de data até fn devolver false. Um
+// elemento de tamanho desconhecido ou maior que o buffer recebe o restante
+// dos bytes disponíveis.
+func eachElement(data []byte, fn func(id uint64, body []byte) bool) {
+	for len(data) > 0 {
+		id, idLen, ok := readVint(data, true)
+		if !ok {
+			return
+		}
+		size, sizeLen, ok := readVint(data[idLen:], false)
+		if !ok {
+			return
+		}
+
+		start := idLen + sizeLen
+		end := uint64(len(data))
+		if size != unknownSize(sizeLen) && uint64(start)+size < end {
+			end = uint64(start) + size
+		}
+
+		if !fn(id, data[start:end]) {
+			return
+		}
+		data = data[end:]
+	}
+}
+
+// readVint lê um inteiro de tamanho variável do EBML. IDs mantêm o bit
+// marcador; tamanhos não.
+func readVint(data []byte, keepMarker bool) (uint64, int, bool) {
+	if len(data) == 0 || data[0] == 0 {
+		return 0, 0, false
+	}
+
+	length := 1
+	for mask := byte(0x80); data[0]&mask == 0; mask >>= 1 {
+		length++
+	}
+	if len(data) < length {
+		return 0, 0, false
+	}
+
+	value := uint64(data[0])
+	if !keepMarker {
+		value &= uint64(0xFF >> length)
+	}
+	for _, b := range data[1:length] {
+		value = value<<8 | uint64(b)
+	}
+	return value, length, true
+}
+
+func unknownSize(length int) uint64 {
+	return 1<<(7*length) - 1
+}
+
+func readUint(data []byte) uint64 {
+	var value uint64
+	for _, b := range data {
+		value = value<<8 | uint64(b)
+	}
+	return value
+}
+
+func readFloat(data []byte) float64 {
+	switch len(data) {
+	case 4:
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(data)))
+	case 8:
+		return math.Float64frombits(binary.BigEndian.Uint64(data))
+	}
+	return 0
+}
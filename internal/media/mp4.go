@@ -0,0 +1,186 @@
+package media
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Limite do moov lido para a memória; vídeos comuns ficam bem abaixo disso.
+const maxMoovSize = 64 << 20
+
+var mp4Codecs = map[string]string{
+	"avc1": "h264", "avc3": "h264",
+	"hvc1": "hevc", "hev1": "hevc",
+	"av01": "av1",
+	"vp09": "vp9",
+	"mp4v": "mpeg4",
+}
+
+// probeMP4 percorre as caixas de topo até o moov (que pode estar depois do
+// mdat) e lê mvhd, tkhd e stsd da primeira trilha de vídeo.
+func probeMP4(r io.ReaderAt, size int64) (*Metadata, error) {
+	var offset int64
+	for offset+8 <= size {
+		boxType, boxSize, headerLen, err := readBoxHeader(r, offset, size)
+		if err != nil {
+			return nil, err
+		}
+
+		if boxType == "moov" {
+			if boxSize-headerLen > maxMoovSize {
+				return nil, fmt.Errorf("%w: caixa moov muito grande", ErrMalformed)
+			}
+			moov := make([]byte, boxSize-headerLen)
+			if _, err := r.ReadAt(moov, offset+headerLen); err != nil && !errors.Is(err, io.EOF) {
+				return nil, err
+			}
+			return parseMoov(moov)
+		}
+
+		offset += boxSize
+	}
+	return nil, fmt.Errorf("%w: caixa moov não encontrada", ErrMalformed)
+}
+
+func readBoxHeader(r io.ReaderAt, offset, fileSize int64) (string, int64, int64, error) {
+	buf := make([]byte, 16)
+	n, err := r.ReadAt(buf, offset)
+	if n < 8 {
+		if err == nil || errors.Is(err, io.EOF) {
+			err = fmt.Errorf("%w: caixa truncada", ErrMalformed)
+		}
+		return "", 0, 0, err
+	}
+
+	boxType := string(buf[4:8])
+	boxSize, headerLen := int64(binary.BigEndian.Uint32(buf[0:4])), int64(8)
+	switch boxSize {
+	case 0:
+		boxSize = fileSize - offset
+	case 1:
+		if n < 16 {
+			return "", 0, 0, fmt.Errorf("%w: caixa truncada", ErrMalformed)
+		}
+		boxSize, headerLen = int64(binary.BigEndian.Uint64(buf[8:16])), 16
+	}
+
+	if boxSize < headerLen {
+		return "", 0, 0, fmt.Errorf("%w: tamanho de caixa inválido", ErrMalformed)
+	}
+	return boxType, boxSize, headerLen, nil
+}
+
+// eachBox itera as caixas filhas já em memória.
+func eachBox(data []byte, fn func(boxType string, body []byte)) {
+	for len(data) >= 8 {
+		size, headerLen := uint64(binary.BigEndian.Uint32(data[0:4])), uint64(8)
+		switch size {
+		case 0:
+			size = uint64(len(data))
+		case 1:
+			if len(data) < 16 {
+				return
+			}
+			size, headerLen = binary.BigEndian.Uint64(data[8:16]), 16
+		}
+		if size < headerLen || size > uint64(len(data)) {
+			return
+		}
+
+		fn(string(data[4:8]), data[headerLen:size])
+		data = data[size:]
+	}
+}
+
+func findBox(data []byte, path ...string) []byte {
+	for _, name := range path {
+		var found []byte
+		eachBox(data, func(boxType string, body []byte) {
+			if found == nil && boxType == name {
+				found = body
+			}
+		})
+		if found == nil {
+			return nil
+		}
+		data = found
+	}
+	return data
+}
+
+func parseMoov(moov []byte) (*Metadata, error) {
+	meta := &Metadata{}
+
+	mvhd := findBox(moov, "mvhd")
+	if mvhd == nil {
+		return nil, fmt.Errorf("%w: caixa mvhd não encontrada", ErrMalformed)
+	}
+	meta.Duration = parseMvhd(mvhd)
+
+	eachBox(moov, func(boxType string, trak []byte) {
+		if boxType != "trak" || meta.VideoCodec != "" {
+			return
+		}
+		hdlr := findBox(trak, "mdia", "hdlr")
+		if len(hdlr) < 12 || string(hdlr[8:12]) != "vide" {
+			return
+		}
+
+		stsd := findBox(trak, "mdia", "minf", "stbl", "stsd")
+		if len(stsd) >= 44 {
+			fourcc := string(stsd[12:16])
+			meta.VideoCodec = fourcc
+			if codec, ok := mp4Codecs[fourcc]; ok {
+				meta.VideoCodec = codec
+			}
+			meta.Width = int(binary.BigEndian.Uint16(stsd[40:42]))
+			meta.Height = int(binary.BigEndian.Uint16(stsd[42:44]))
+		}
+
+		// As dimensões do tkhd já consideram o aspecto de exibição.
+		if w, h := parseTkhd(findBox(trak, "tkhd")); w > 0 && h > 0 {
+			meta.Width, meta.Height = w, h
+		}
+	})
+
+	return meta, nil
+}
+
+func parseMvhd(mvhd []byte) time.Duration {
+	var timescale, duration uint64
+	switch {
+	case len(mvhd) >= 32 && mvhd[0] == 1:
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+		if duration == ^uint64(0) {
+			return 0
+		}
+	case len(mvhd) >= 20 && mvhd[0] == 0:
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+		if duration == uint64(^uint32(0)) {
+			return 0
+		}
+	}
+	if timescale == 0 {
+		return 0
+	}
+	return time.Duration(float64(duration) / float64(timescale) * float64(time.Second))
+}
+
+// parseTkhd devolve largura e altura (ponto fixo 16.16) do cabeçalho da trilha.
+func parseTkhd(tkhd []byte) (int, int) {
+	offset := 76
+	if len(tkhd) > 0 && tkhd[0] == 1 {
+		offset = 88
+	}
+	if len(tkhd) < offset+8 {
+		return 0, 0
+	}
+	width := binary.BigEndian.Uint32(tkhd[offset : offset+4])
+	height := binary.BigEndian.Uint32(tkhd[offset+4 : offset+8])
+	return int(width >> 16), int(height >> 16)
+}
@@ -0,0 +1,53 @@
+package media
+
+import (
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrMalformed indica um contêiner reconhecido, mas que não pôde ser lido.
+var ErrMalformed = errors.New("contêiner de vídeo inválido")
+
+// ErrProbeUnsupported é devolvido para formatos aceitos sem parser de
+// metadados (ex.: AVI).
+var ErrProbeUnsupported = errors.New("metadados não suportados para este formato")
+
+// Metadata é o que se sabe do vídeo antes do processamento. Campos zerados
+// não foram encontrados no contêiner.
+type Metadata struct {
+	Duration   time.Duration
+	Width      int
+	Height     int
+	VideoCodec string
+	// Bitrate é a média do arquivo inteiro, em bits por segundo.
+	Bitrate int64
+}
+
+// Probe lê os metadados do contêiner sem decodificar o vídeo. Usa poucas
+// leituras grandes, para funcionar também sobre objetos remotos (S3).
+func Probe(r io.ReaderAt, size int64) (*Metadata, error) {
+	header := make([]byte, SniffLength)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	var meta *Metadata
+	switch Sniff(header[:n]) {
+	case FormatMP4:
+		meta, err = probeMP4(r, size)
+	case FormatMKV:
+		meta, err = probeMKV(r, size)
+	default:
+		return nil, ErrProbeUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if meta.Duration > 0 {
+		meta.Bitrate = int64(float64(size*8) / meta.Duration.Seconds())
+	}
+	return meta, nil
+}
@@ -0,0 +1,130 @@
+package media_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hackaton-service-api/internal/media"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func box(boxType string, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	out := binary.BigEndian.AppendUint32(nil, uint32(8+len(body)))
+	return append(append(out, boxType...), body...)
+}
+
+func u32(values ...uint32) []byte {
+	var out []byte
+	for _, v := range values {
+		out = binary.BigEndian.AppendUint32(out, v)
+	}
+	return out
+}
+
+// sampleMP4 monta um MP4 mínimo com o moov depois do mdat (sem faststart).
+func sampleMP4(timescale, duration uint32, width, height uint16) []byte {
+	mvhd := box("mvhd", u32(0, 0, 0, timescale, duration))
+
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], uint32(height)<<16)
+
+	entry := make([]byte, 36)
+	binary.BigEndian.PutUint16(entry[24:], width)
+	binary.BigEndian.PutUint16(entry[26:], height)
+	stsd := box("stsd", u32(0, 1), box("avc1", entry))
+
+	trak := box("trak",
+		box("tkhd", tkhd),
+		box("mdia",
+			box("hdlr", u32(0, 0), []byte("vide")),
+			box("minf", box("stbl", stsd)),
+		),
+	)
+	soundTrak := box("trak", box("mdia", box("hdlr", u32(0, 0), []byte("soun"))))
+
+	return bytes.Join([][]byte{
+		box("ftyp", []byte("isom"), u32(0x200)),
+		box("mdat", make([]byte, 4096)),
+		box("moov", mvhd, soundTrak, trak),
+	}, nil)
+}
+
+func element(id []byte, payload ...[]byte) []byte {
+	body := bytes.Join(payload, nil)
+	size := binary.BigEndian.AppendUint64(nil, uint64(len(body)))
+	size[0] = 0x01
+	return append(append(append([]byte{}, id...), size...), body...)
+}
+
+func sampleMKV() []byte {
+	header := element([]byte{0x1A, 0x45, 0xDF, 0xA3}, element([]byte{0x42, 0x82}, []byte("matroska")))
+	info := element([]byte{0x15, 0x49, 0xA9, 0x66},
+		element([]byte{0x2A, 0xD7, 0xB1}, u32(1000000)),
+		element([]byte{0x44, 0x89}, binary.BigEndian.AppendUint64(nil, math.Float64bits(90000))),
+	)
+	tracks := element([]byte{0x16, 0x54, 0xAE, 0x6B},
+		element([]byte{0xAE}, element([]byte{0x83}, []byte{2}), element([]byte{0x86}, []byte("A_OPUS"))),
+		element([]byte{0xAE},
+			element([]byte{0x83}, []byte{1}),
+			element([]byte{0x86}, []byte("V_VP9")),
+			element([]byte{0xE0}, element([]byte{0xB0}, []byte{0x05, 0x00}), element([]byte{0xBA}, []byte{0x02, 0xD0})),
+		),
+	)
+	cluster := element([]byte{0x1F, 0x43, 0xB6, 0x75}, make([]byte, 1024))
+
+	// Segmento com tamanho desconhecido, como gravam os muxers ao vivo.
+	segment := append([]byte{0x18, 0x53, 0x80, 0x67, 0x01, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, bytes.Join([][]byte{info, tracks, cluster}, nil)...)
+	return append(header, segment...)
+}
+
+func TestProbe_MP4(t *testing.T) {
+	data := sampleMP4(1000, 12500, 1920, 1080)
+
+	meta, err := media.Probe(bytes.NewReader(data), int64(len(data)))
+
+	require.NoError(t, err)
+	assert.Equal(t, 12500*time.Millisecond, meta.Duration)
+	assert.Equal(t, 1920, meta.Width)
+	assert.Equal(t, 1080, meta.Height)
+	assert.Equal(t, "h264", meta.VideoCodec)
+	assert.Equal(t, int64(float64(len(data)*8)/12.5), meta.Bitrate)
+}
+
+func TestProbe_MKV(t *testing.T) {
+	data := sampleMKV()
+
+	meta, err := media.Probe(bytes.NewReader(data), int64(len(data)))
+
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, meta.Duration)
+	assert.Equal(t, 1280, meta.Width)
+	assert.Equal(t, 720, meta.Height)
+	assert.Equal(t, "vp9", meta.VideoCodec)
+}
+
+func TestProbe_Errors(t *testing.T) {
+	t.Run("MP4 sem moov", func(t *testing.T) {
+		data := box("ftyp", []byte("isom"), u32(0x200))
+		_, err := media.Probe(bytes.NewReader(data), int64(len(data)))
+		assert.ErrorIs(t, err, media.ErrMalformed)
+	})
+
+	t.Run("Caixa com tamanho inválido", func(t *testing.T) {
+		data := append(box("ftyp", []byte("isom"), u32(0x200)), u32(4)...)
+		data = append(data, "moov"...)
+		_, err := media.Probe(bytes.NewReader(data), int64(len(data)))
+		assert.ErrorIs(t, err, media.ErrMalformed)
+	})
+
+	t.Run("AVI sem parser", func(t *testing.T) {
+		data := []byte("RIFF\x10\x00\x00\x00AVI LIST")
+		_, err := media.Probe(bytes.NewReader(data), int64(len(data)))
+		assert.ErrorIs(t, err, media.ErrProbeUnsupported)
+	})
+}
@@ -0,0 +1,84 @@
+package media
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+)
+
+type Format string
+
+const (
+	FormatMP4 Format = "mp4"
+	FormatMKV Format = "mkv"
+	FormatAVI Format = "avi"
+)
+
+// SniffLength é quantos bytes do início do arquivo Sniff precisa ler.
+const SniffLength = 64
+
+var ebmlMagic = []byte{0x1A, 0x45, 0xDF, 0xA3}
+
+// Marcas ftyp de formatos ISO-BMFF que não são vídeo (imagens HEIF/AVIF e
+// áudio M4A/M4B), rejeitadas mesmo tendo o mesmo contêiner do MP4.
+var nonVideoBrands = map[string]bool{
+	"heic": true, "heix": true, "mif1": true, "msf1": true, "avif": true,
+	"M4A ": true, "M4B ": true, "M4P ": true,
+}
+
+// Sniff identifica o contêiner pelos primeiros bytes do arquivo (caixa ftyp
+// no MP4, cabeçalho EBML com DocType matroska no MKV e RIFF/AVI no AVI).
+// Devolve "" quando não reconhece.
+func Sniff(header []byte) Format {
+	switch {
+	case len(header) >= 12 && string(header[4:8]) == "ftyp":
+		if nonVideoBrands[string(header[8:12])] {
+			return ""
+		}
+		return FormatMP4
+	case bytes.HasPrefix(header, ebmlMagic) && bytes.Contains(header, []byte("matroska")):
+		return FormatMKV
+	case len(header) >= 12 && string(header[0:4]) == "RIFF" && string(header[8:12]) == "AVI ":
+		return FormatAVI
+	}
+	return ""
+}
+
+// FromExtension é usado quando o conteúdo ainda não está disponível (ex.: ao
+// iniciar um upload em partes).
+func FromExtension(fileName string) Format {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".mp4", ".m4v":
+		return FormatMP4
+	case ".mkv":
+		return FormatMKV
+	case ".avi":
+		return FormatAVI
+	}
+	return ""
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatMP4:
+		return "video/mp4"
+	case FormatMKV:
+		return "video/x-matroska"
+	case FormatAVI:
+		return "video/x-msvideo"
+	}
+	return "application/octet-stream"
+}
+
+// ParseFormats lê uma lista separada por vírgula (ex.: "mp4,mkv"), ignorando
+// entradas desconhecidas.
+func ParseFormats(list string) []Format {
+	var formats []Format
+	for _, item := range strings.Split(list, ",") {
+		switch f := Format(strings.ToLower(strings.TrimSpace(item))); f {
+		case FormatMP4, FormatMKV, FormatAVI:
+			formats = append(formats, f)
+		}
+	}
+	return formats
+}
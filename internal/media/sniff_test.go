@@ -0,0 +1,35 @@
+package media_test
+
+import (
+	"hackaton-service-api/internal/media"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []byte
+		want   media.Format
+	}{
+		{"MP4", []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"), media.FormatMP4},
+		{"MKV", append([]byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F, 0x42, 0x82, 0x88}, "matroska"...), media.FormatMKV},
+		{"WebM não é MKV", append([]byte{0x1A, 0x45, 0xDF, 0xA3, 0x9F, 0x42, 0x82, 0x84}, "webm"...), ""},
+		{"AVI", []byte("RIFF\x10\x00\x00\x00AVI LIST"), media.FormatAVI},
+		{"WAV não é AVI", []byte("RIFF\x10\x00\x00\x00WAVEfmt "), ""},
+		{"Imagem HEIC", []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"), ""},
+		{"PDF renomeado", []byte("%PDF-1.7\n%\xe2\xe3\xcf\xd3"), ""},
+		{"Arquivo curto", []byte("RIFF"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, media.Sniff(tt.header))
+		})
+	}
+}
+
+func TestParseFormats(t *testing.T) {
+	assert.Equal(t, []media.Format{media.FormatMP4, media.FormatAVI}, media.ParseFormats(" MP4, avi,mov"))
+}
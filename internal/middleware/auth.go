@@ -8,15 +8,22 @@ import (
 )
 
 type TokenValidator interface {
-	ValidateToken(token string) (string, error)
+	ValidateToken(token string) (string, string, error)
+}
+
+// SessionChecker garante que tokens de sessões revogadas (logout) deixem de
+// ser aceitos antes mesmo de expirarem.
+type SessionChecker interface {
+	IsSessionActive(sessionID string) bool
 }
 
 type AuthMiddleware struct {
 	Validator TokenValidator
+	Sessions  SessionChecker
 }
 
-func NewAuthMiddleware(validator TokenValidator) *AuthMiddleware {
-	return &AuthMiddleware{Validator: validator}
+func NewAuthMiddleware(validator TokenValidator, sessions SessionChecker) *AuthMiddleware {
+	return &AuthMiddleware{Validator: validator, Sessions: sessions}
 }
 
 func (m *AuthMiddleware) Handle() gin.HandlerFunc {
@@ -35,14 +42,21 @@ func (m *AuthMiddleware) Handle() gin.HandlerFunc {
 			return
 		}
 
-		userID, err := m.Validator.ValidateToken(parts[1])
+		userID, sessionID, err := m.Validator.ValidateToken(parts[1])
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token inválido ou expirado"})
 			c.Abort()
 			return
 		}
 
+		if !m.Sessions.IsSessionActive(sessionID) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Sessão encerrada"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", userID)
+		c.Set("sessionID", sessionID)
 		c.Next()
 	}
 }
\ No newline at end of file
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Janela aceita entre o X-Timestamp assinado e o relógio da API.
+const maxSignatureSkew = 5 * time.Minute
+
+// ServiceAuthMiddleware autentica chamadas entre serviços (ex.: o worker).
+// Aceita um token de serviço fixo no header Authorization ou uma assinatura
+// HMAC-SHA256 do corpo da requisição nos headers X-Timestamp e X-Signature.
+type ServiceAuthMiddleware struct {
+	Token  string
+	Secret string
+}
+
+func NewServiceAuthMiddleware(token, secret string) *ServiceAuthMiddleware {
+	return &ServiceAuthMiddleware{Token: token, Secret: secret}
+}
+
+func (m *ServiceAuthMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.Token == "" && m.Secret == "" {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API interna não configurada"})
+			c.Abort()
+			return
+		}
+
+		if signature := c.GetHeader("X-Signature"); signature != "" && m.Secret != "" {
+			if !m.validSignature(c, signature) {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Assinatura inválida"})
+				c.Abort()
+				return
+			}
+		} else if !m.validToken(c.GetHeader("Authorization")) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Credenciais de serviço inválidas"})
+			c.Abort()
+			return
+		}
+
+		service := c.GetHeader("X-Service-Name")
+		if service == "" {
+			service = "worker"
+		}
+		c.Set("serviceName", service)
+		c.Next()
+	}
+}
+
+func (m *ServiceAuthMiddleware) validToken(authHeader string) bool {
+	if m.Token == "" {
+		return false
+	}
+	token, found := strings.CutPrefix(authHeader, "Bearer ")
+	return found && subtle.ConstantTimeCompare([]byte(token), []byte(m.Token)) == 1
+}
+
+// validSignature confere HMAC(secret, timestamp + "\n" + método + "\n" + path + "\n" + corpo).
+func (m *ServiceAuthMiddleware) validSignature(c *gin.Context, signature string) bool {
+	ts, err := strconv.ParseInt(c.GetHeader("X-Timestamp"), 10, 64)
+	if err != nil {
+		return false
+	}
+
+	skew := time.Since(time.Unix(ts, 0))
+	if skew > maxSignatureSkew || skew < -maxSignatureSkew {
+		return false
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	expected := SignRequest(m.Secret, ts, c.Request.Method, c.Request.URL.Path, body)
+	return hmac.Equal([]byte(signature), []byte(expected))
+}
+
+// SignRequest gera a assinatura esperada em X-Signature. É exportada para que
+// clientes Go (e os testes do worker) gerem assinaturas compatíveis.
+func SignRequest(secret string, timestamp int64, method, path string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "\n" + method + "\n" + path + "\n"))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
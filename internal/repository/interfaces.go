@@ -1,12 +1,52 @@
 package repository
 
-import "hackaton-service-api/internal/entity"
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"time"
+)
+
+// ErrNotFound é devolvido pelos repositórios quando o registro não existe,
+// para que as camadas de cima não dependam dos erros do GORM.
+var ErrNotFound = errors.New("registro não encontrado")
+
+// ErrConcurrentUpdate indica que o registro mudou desde a leitura (versão
+// diferente); quem chamou deve recarregar e decidir de novo.
+var ErrConcurrentUpdate = errors.New("registro alterado por outra operação")
+
+// VideoUsage é o consumo de um usuário. StoredBytes e InFlight consideram só
+// vídeos não removidos; UploadsSince conta também os removidos, para que
+// apagar um vídeo não libere o limite de envios.
+type VideoUsage struct {
+	StoredBytes  int64
+	InFlight     int64
+	UploadsSince int64
+}
 
 type VideoRepository interface {
 	Create(video *entity.Video) error
+	// CreateWithOutbox e UpdateStatusWithOutbox gravam a mensagem para a fila
+	// na mesma transação que o vídeo.
+	CreateWithOutbox(video *entity.Video, message *entity.OutboxMessage) error
 	FindByID(id string) (*entity.Video, error)
-	FindAllByUserID(userID string) ([]entity.Video, error)
+	// FindPage aplica filtros, ordenação e cursor; Count ignora After e Limit.
+	FindPage(filter VideoFilter) ([]entity.Video, error)
+	Count(filter VideoFilter) (int64, error)
 	Update(video *entity.Video) error
+	UpdateStatus(video *entity.Video, event *entity.VideoStatusEvent) error
+	UpdateStatusWithOutbox(video *entity.Video, event *entity.VideoStatusEvent, message *entity.OutboxMessage) error
+	FindStatusHistory(videoID string) ([]entity.VideoStatusEvent, error)
+	// Delete é o soft delete; Purge remove de vez o vídeo e seus dependentes.
+	Delete(video *entity.Video) error
+	FindDeletedBefore(before time.Time, limit int) ([]entity.Video, error)
+	// FindStale devolve vídeos em PENDING ou PROCESSING sem sinal de vida
+	// (ActivityAt) desde activeBefore cujo backoff de reenvio já passou em now.
+	FindStale(activeBefore, now time.Time, limit int) ([]entity.Video, error)
+	// FindAbandonedUploads devolve vídeos em UPLOADING criados antes de
+	// idleBefore e sem parte recebida desde então.
+	FindAbandonedUploads(idleBefore time.Time, limit int) ([]entity.Video, error)
+	Purge(videoID string) error
+	Usage(userID string, since time.Time) (*VideoUsage, error)
 }
 
 type UserRepository interface {
@@ -14,4 +54,76 @@ type UserRepository interface {
 	FindByUsername(username string) (*entity.User, error)
 	FindByEmail(email string) (*entity.User, error)
 	FindByID(id string) (*entity.User, error)
+	Update(user *entity.User) error
+	// MarkVerificationSent grava at como envio do link de confirmação só se o
+	// anterior foi antes de sentBefore; false indica um envio mais recente.
+	MarkVerificationSent(userID string, at, sentBefore time.Time) (bool, error)
+}
+
+type OutboxRepository interface {
+	// ClaimDue reserva até limit mensagens pendentes adiando NextAttemptAt
+	// por lease, para que outra réplica não as publique ao mesmo tempo.
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]entity.OutboxMessage, error)
+	Update(message *entity.OutboxMessage) error
+	DeleteSentBefore(before time.Time) (int64, error)
+}
+
+type BatchRepository interface {
+	Create(batch *entity.Batch) error
+	FindByID(id string) (*entity.Batch, error)
+	FindRecentByUserID(userID string, limit int) ([]entity.Batch, error)
+}
+
+type PlanRepository interface {
+	FindByID(id string) (*entity.Plan, error)
+	// EnsureExists cria o plano se ainda não existir, sem sobrescrever
+	// limites já ajustados no banco.
+	EnsureExists(plan *entity.Plan) error
+}
+
+type PresetRepository interface {
+	// Create e Update desmarcam o preset padrão anterior na mesma transação
+	// quando o preset gravado é o novo padrão.
+	Create(preset *entity.Preset) error
+	Update(preset *entity.Preset) error
+	FindByID(id string) (*entity.Preset, error)
+	FindByName(userID, name string) (*entity.Preset, error)
+	FindDefault(userID string) (*entity.Preset, error)
+	FindAllByUserID(userID string) ([]entity.Preset, error)
+	Delete(preset *entity.Preset) error
+}
+
+type ShareRepository interface {
+	Create(share *entity.Share) error
+	FindByID(id string) (*entity.Share, error)
+	FindByTokenHash(hash string) (*entity.Share, error)
+	FindAllByVideoID(videoID string) ([]entity.Share, error)
+	// Revoke grava at como revogação só se o link ainda não foi revogado, sem
+	// tocar nos demais campos.
+	Revoke(id string, at time.Time) error
+	// RegisterDownload conta um download só se o link ainda vale em at; false
+	// indica que ele foi revogado, expirou ou atingiu o limite desde a
+	// leitura. Um download também zera as senhas incorretas.
+	RegisterDownload(id string, at time.Time) (bool, error)
+	// RegisterPasswordFailure conta uma senha incorreta; ao chegar em
+	// maxFailures, zera a contagem e bloqueia o link até lockUntil.
+	RegisterPasswordFailure(id string, maxFailures int, lockUntil time.Time) error
+}
+
+type UploadPartRepository interface {
+	Save(part *entity.UploadPart) error
+	FindAllByVideoID(videoID string) ([]entity.UploadPart, error)
+	DeleteAllByVideoID(videoID string) error
+}
+
+type SessionRepository interface {
+	Create(session *entity.Session) error
+	FindByID(id string) (*entity.Session, error)
+	FindByTokenHash(hash string) (*entity.Session, error)
+	FindActiveByUserID(userID string) ([]entity.Session, error)
+	// Rotate grava o novo refresh token da sessão só se o atual ainda é
+	// currentHash; false indica que outra renovação usou o mesmo token antes.
+	Rotate(session *entity.Session, currentHash string) (bool, error)
+	Revoke(id string, at time.Time) error
+	RevokeAllByUserID(userID string) error
 }
\ No newline at end of file
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"hackaton-service-api/internal/entity"
+	"time"
+)
+
+// VideoSort é a ordenação da listagem de vídeos. O desempate é sempre pelo
+// id, na mesma direção, para que a paginação por cursor seja estável.
+type VideoSort string
+
+const (
+	SortCreatedDesc VideoSort = "-created_at"
+	SortCreatedAsc  VideoSort = "created_at"
+	SortUpdatedDesc VideoSort = "-updated_at"
+	SortUpdatedAsc  VideoSort = "updated_at"
+)
+
+func (s VideoSort) Valid() bool {
+	switch s {
+	case SortCreatedDesc, SortCreatedAsc, SortUpdatedDesc, SortUpdatedAsc:
+		return true
+	}
+	return false
+}
+
+// Column devolve a coluna ordenada e se a ordem é decrescente.
+func (s VideoSort) Column() (string, bool) {
+	if s[0] == '-' {
+		return string(s[1:]), true
+	}
+	return string(s), false
+}
+
+// VideoCursor é a posição do último item da página anterior (keyset).
+type VideoCursor struct {
+	SortValue time.Time
+	ID        string
+}
+
+// VideoFilter descreve uma página da listagem de vídeos de um usuário.
+// Campos zerados não filtram; CreatedTo é exclusivo.
+type VideoFilter struct {
+	UserID      string
+	Statuses    []entity.VideoStatus
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	FileName    string
+	BatchID     string
+	Sort        VideoSort
+	After       *VideoCursor
+	Limit       int
+}
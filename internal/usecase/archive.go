@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidArchive = errors.New("pedido de download inválido")
+	ErrVideoNotReady  = errors.New("vídeo não está pronto")
+)
+
+// Vídeos aceitos em um único ZIP combinado.
+const MaxArchiveVideos = 50
+
+// ArchiveRequest escolhe os vídeos do ZIP: uma lista de IDs, em que todos
+// precisam estar DONE, ou um lote, do qual entram só os vídeos DONE.
+type ArchiveRequest struct {
+	VideoIDs []string
+	BatchID  string
+}
+
+// Archive é um ZIP montado sob demanda a partir das saídas dos vídeos. Os
+// arquivos são gravados sem compressão (já são ZIPs de imagens) e com datas
+// fixas, então os mesmos vídeos geram sempre os mesmos bytes: Size e ETag
+// são conhecidos antes do envio e um download interrompido pode ser retomado
+// por Range.
+type Archive struct {
+	FileName string
+	Size     int64
+	ETag     string
+	ModTime  time.Time
+
+	entries []archiveEntry
+	storage FileStorageService
+}
+
+type archiveEntry struct {
+	name     string
+	key      string
+	size     int64
+	modified time.Time
+}
+
+// errRangeDone interrompe a montagem do ZIP quando o trecho pedido já foi
+// escrito.
+var errRangeDone = errors.New("intervalo concluído")
+
+func (uc *VideoUseCase) PrepareArchive(userID string, req ArchiveRequest) (*Archive, error) {
+	videos, fileName, err := uc.archiveVideos(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &Archive{FileName: fileName, storage: uc.Storage}
+	used := map[string]bool{}
+	hash := sha256.New()
+	for _, video := range videos {
+		size, err := uc.Storage.ObjectSize(video.OutputKey)
+		if err != nil {
+			return nil, fmt.Errorf("vídeo %s: %w", video.ID, err)
+		}
+
+		modified := video.UpdatedAt
+		if video.ProcessingFinishedAt != nil {
+			modified = *video.ProcessingFinishedAt
+		}
+		entry := archiveEntry{
+			name:     archiveEntryName(video, used),
+			key:      video.OutputKey,
+			size:     size,
+			modified: modified.UTC().Truncate(time.Second),
+		}
+		archive.entries = append(archive.entries, entry)
+		if entry.modified.After(archive.ModTime) {
+			archive.ModTime = entry.modified
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00%d\x00%s\x00%d\n", video.ID, entry.key, entry.size, entry.name, entry.modified.Unix())
+	}
+	archive.ETag = `"` + hex.EncodeToString(hash.Sum(nil))[:32] + `"`
+
+	// O tamanho vem de uma montagem de ensaio com o conteúdo zerado: o
+	// layout do ZIP depende só dos nomes, datas e tamanhos.
+	var counter countingWriter
+	if err := archive.write(&counter, zeroContent); err != nil {
+		return nil, err
+	}
+	archive.Size = counter.n
+
+	return archive, nil
+}
+
+func (uc *VideoUseCase) archiveVideos(userID string, req ArchiveRequest) ([]entity.Video, string, error) {
+	if (len(req.VideoIDs) == 0) == (req.BatchID == "") {
+		return nil, "", fmt.Errorf("%w: informe os IDs dos vídeos ou o ID do lote", ErrInvalidArchive)
+	}
+
+	if req.BatchID != "" {
+		if _, err := uuid.Parse(req.BatchID); err != nil {
+			return nil, "", fmt.Errorf("%w: batch_id inválido", ErrInvalidArchive)
+		}
+		videos, err := uc.Repo.FindPage(repository.VideoFilter{
+			UserID:   userID,
+			BatchID:  req.BatchID,
+			Statuses: []entity.VideoStatus{entity.StatusDone},
+			Sort:     repository.SortCreatedAsc,
+			Limit:    MaxArchiveVideos,
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		if len(videos) == 0 {
+			return nil, "", fmt.Errorf("%w: nenhum vídeo pronto no lote", repository.ErrNotFound)
+		}
+		return videos, "lote_" + req.BatchID + ".zip", nil
+	}
+
+	ids := uniqueIDs(req.VideoIDs)
+	if len(ids) > MaxArchiveVideos {
+		return nil, "", fmt.Errorf("%w: no máximo %d vídeos por download", ErrInvalidArchive, MaxArchiveVideos)
+	}
+
+	videos := make([]entity.Video, 0, len(ids))
+	for _, id := range ids {
+		video, err := uc.findOwned(userID, id)
+		if err != nil {
+			return nil, "", err
+		}
+		if !video.DownloadAvailable() {
+			return nil, "", fmt.Errorf("%w: %s", ErrVideoNotReady, id)
+		}
+		videos = append(videos, *video)
+	}
+	return videos, "videos.zip", nil
+}
+
+// WriteRange escreve os bytes [offset, offset+length) do ZIP. O CRC de cada
+// arquivo vai no diretório central, no fim do ZIP, então os objetos antes de
+// offset também são lidos do armazenamento, só não são enviados.
+func (a *Archive) WriteRange(w io.Writer, offset, length int64) error {
+	err := a.write(&rangeWriter{w: w, skip: offset, remaining: length}, a.readEntry)
+	if errors.Is(err, errRangeDone) {
+		return nil
+	}
+	return err
+}
+
+func (a *Archive) write(w io.Writer, open func(archiveEntry) (io.ReadCloser, error)) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range a.entries {
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: entry.name, Method: zip.Store, Modified: entry.modified})
+		if err != nil {
+			return err
+		}
+
+		body, err := open(entry)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.name, err)
+		}
+		n, err := io.Copy(fw, io.LimitReader(body, entry.size))
+		body.Close()
+		if err != nil {
+			return err
+		}
+		if n != entry.size {
+			return fmt.Errorf("%s: objeto com %d bytes, esperado %d", entry.name, n, entry.size)
+		}
+	}
+	return zw.Close()
+}
+
+func (a *Archive) readEntry(entry archiveEntry) (io.ReadCloser, error) {
+	return a.storage.ReadObject(entry.key)
+}
+
+func zeroContent(entry archiveEntry) (io.ReadCloser, error) {
+	return io.NopCloser(io.LimitReader(zeroReader{}, entry.size)), nil
+}
+
+// archiveEntryName é o outputFileName do vídeo, numerando os repetidos.
+func archiveEntryName(video entity.Video, used map[string]bool) string {
+	ext := path.Ext(video.OutputKey)
+	base := strings.TrimSuffix(outputFileName(video), ext)
+
+	name := base + ext
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s (%d)%s", base, i, ext)
+	}
+	used[name] = true
+	return name
+}
+
+func uniqueIDs(ids []string) []string {
+	seen := map[string]bool{}
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// rangeWriter descarta os primeiros skip bytes, repassa os remaining
+// seguintes e então interrompe a escrita com errRangeDone.
+type rangeWriter struct {
+	w         io.Writer
+	skip      int64
+	remaining int64
+}
+
+func (r *rangeWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if r.skip > 0 {
+		k := min(r.skip, int64(len(p)))
+		r.skip -= k
+		p = p[k:]
+	}
+	if len(p) > 0 && r.remaining > 0 {
+		k := min(r.remaining, int64(len(p)))
+		if _, err := r.w.Write(p[:k]); err != nil {
+			return 0, err
+		}
+		r.remaining -= k
+	}
+	if r.remaining == 0 {
+		return n, errRangeDone
+	}
+	return n, nil
+}
@@ -0,0 +1,122 @@
+package usecase_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoUseCase_PrepareArchive(t *testing.T) {
+	finished := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	done := func(id, fileName string) *entity.Video {
+		return &entity.Video{ID: id, UserID: "u1", FileName: fileName, Status: entity.StatusDone, OutputKey: "outputs/" + id + ".zip", ProcessingFinishedAt: &finished}
+	}
+	contents := map[string][]byte{
+		"outputs/v1.zip": bytes.Repeat([]byte("frames-1"), 1000),
+		"outputs/v2.zip": []byte("frames-2"),
+	}
+
+	setup := func() (*usecase.VideoUseCase, *MockVideoRepository, *MockStorageService) {
+		repo, storage := new(MockVideoRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, nil, storage, nil)
+		for key, content := range contents {
+			storage.On("ObjectSize", key).Return(int64(len(content)), nil)
+		}
+		return uc, repo, storage
+	}
+	// Cada montagem lê o objeto de novo, então cada leitura tem o seu leitor.
+	expectReads := func(storage *MockStorageService, times int) {
+		for key, content := range contents {
+			for range times {
+				storage.On("ReadObject", key).Return(io.NopCloser(bytes.NewReader(content)), nil).Once()
+			}
+		}
+	}
+
+	t.Run("Sucesso: ZIP com os vídeos informados", func(t *testing.T) {
+		uc, repo, storage := setup()
+		repo.On("FindByID", "v1").Return(done("v1", "aula.mp4"), nil)
+		repo.On("FindByID", "v2").Return(done("v2", "dir/aula.mkv"), nil)
+		expectReads(storage, 1)
+
+		archive, err := uc.PrepareArchive("u1", usecase.ArchiveRequest{VideoIDs: []string{"v1", "v2", "v1"}})
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		require.NoError(t, archive.WriteRange(&buf, 0, archive.Size))
+		assert.Equal(t, archive.Size, int64(buf.Len()))
+		assert.Equal(t, "videos.zip", archive.FileName)
+
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		require.Len(t, zr.File, 2)
+		assert.Equal(t, "aula.zip", zr.File[0].Name)
+		assert.Equal(t, "aula (2).zip", zr.File[1].Name)
+
+		f, err := zr.File[0].Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		assert.Equal(t, contents["outputs/v1.zip"], content)
+	})
+
+	t.Run("Sucesso: Range devolve o mesmo trecho do ZIP completo", func(t *testing.T) {
+		uc, repo, storage := setup()
+		repo.On("FindByID", "v1").Return(done("v1", "a.mp4"), nil)
+		repo.On("FindByID", "v2").Return(done("v2", "b.mp4"), nil)
+		expectReads(storage, 2)
+
+		archive, err := uc.PrepareArchive("u1", usecase.ArchiveRequest{VideoIDs: []string{"v1", "v2"}})
+		require.NoError(t, err)
+
+		var full, part bytes.Buffer
+		require.NoError(t, archive.WriteRange(&full, 0, archive.Size))
+		require.NoError(t, archive.WriteRange(&part, 100, archive.Size-100))
+		assert.Equal(t, full.Bytes()[100:], part.Bytes())
+	})
+
+	t.Run("Sucesso: Lote inclui só vídeos DONE", func(t *testing.T) {
+		uc, repo, _ := setup()
+		repo.On("FindPage", mock.MatchedBy(func(f repository.VideoFilter) bool {
+			return f.UserID == "u1" && f.BatchID == "9f1c0c56-7a4e-4a43-9a59-1b0f7f3e2d10" &&
+				len(f.Statuses) == 1 && f.Statuses[0] == entity.StatusDone
+		})).Return([]entity.Video{*done("v2", "b.mp4")}, nil)
+
+		archive, err := uc.PrepareArchive("u1", usecase.ArchiveRequest{BatchID: "9f1c0c56-7a4e-4a43-9a59-1b0f7f3e2d10"})
+		require.NoError(t, err)
+		assert.Equal(t, "lote_9f1c0c56-7a4e-4a43-9a59-1b0f7f3e2d10.zip", archive.FileName)
+		assert.NotEmpty(t, archive.ETag)
+	})
+
+	t.Run("Erro: Vídeo ainda não processado", func(t *testing.T) {
+		uc, repo, _ := setup()
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusProcessing}, nil)
+
+		_, err := uc.PrepareArchive("u1", usecase.ArchiveRequest{VideoIDs: []string{"v1"}})
+		assert.ErrorIs(t, err, usecase.ErrVideoNotReady)
+	})
+
+	t.Run("Erro: Vídeo de outro usuário", func(t *testing.T) {
+		uc, repo, _ := setup()
+		repo.On("FindByID", "v1").Return(done("v1", "a.mp4"), nil)
+
+		_, err := uc.PrepareArchive("u2", usecase.ArchiveRequest{VideoIDs: []string{"v1"}})
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+
+	t.Run("Erro: IDs e lote juntos", func(t *testing.T) {
+		uc, _, _ := setup()
+
+		_, err := uc.PrepareArchive("u1", usecase.ArchiveRequest{VideoIDs: []string{"v1"}, BatchID: "b1"})
+		assert.ErrorIs(t, err, usecase.ErrInvalidArchive)
+	})
+}
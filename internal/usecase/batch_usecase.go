@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"mime/multipart"
+	"strings"
+	"sync"
+)
+
+var ErrInvalidBatch = errors.New("lote inválido")
+
+const (
+	MaxBatchFiles = 20
+	// Lotes listados em GET /api/batches.
+	recentBatchesLimit = 50
+)
+
+// BatchFile é um arquivo do lote. Open é chamado só quando o arquivo vai ser
+// enviado, para não manter todos abertos ao mesmo tempo.
+type BatchFile struct {
+	FileName string
+	Size     int64
+	Open     func() (multipart.File, error)
+}
+
+// BatchItemResult é o resultado de um arquivo do lote: o vídeo criado ou o
+// erro que o recusou, com os mesmos erros do upload individual.
+type BatchItemResult struct {
+	FileName string
+	Video    *entity.Video
+	Err      error
+}
+
+// BatchDetail é o lote com os vídeos e a contagem por status. Finished indica
+// que nenhum vídeo do lote ainda está em andamento.
+type BatchDetail struct {
+	entity.Batch
+	Videos       []entity.Video             `json:"videos"`
+	StatusCounts map[entity.VideoStatus]int `json:"status_counts"`
+	Finished     bool                       `json:"finished"`
+}
+
+type BatchUseCase struct {
+	Repo   repository.BatchRepository
+	Videos *VideoUseCase
+	// Concurrency limita os envios simultâneos ao armazenamento por lote.
+	Concurrency int
+}
+
+func NewBatchUseCase(repo repository.BatchRepository, videos *VideoUseCase) *BatchUseCase {
+	return &BatchUseCase{Repo: repo, Videos: videos, Concurrency: 4}
+}
+
+// Upload envia os arquivos com o mesmo fluxo e as mesmas validações do
+// upload individual, no máximo Concurrency por vez. A falha de um arquivo não
+// impede os demais; o erro devolvido é só o do lote como um todo. As cotas do
+// plano são conferidas para o lote inteiro antes do envio: os arquivos além
+// do que o plano permite são recusados sem serem enviados.
+func (uc *BatchUseCase) Upload(userID, name string, files []BatchFile, processing ProcessingRequest) (*entity.Batch, []BatchItemResult, error) {
+	if len(files) == 0 || len(files) > MaxBatchFiles {
+		return nil, nil, fmt.Errorf("%w: envie de 1 a %d arquivos", ErrInvalidBatch, MaxBatchFiles)
+	}
+
+	// Conferido antes de criar o lote, para não gravar um lote só de falhas.
+	user, err := uc.Videos.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("usuário não encontrado")
+	}
+	if err := uc.Videos.Policy.CheckEmail(user); err != nil {
+		return nil, nil, err
+	}
+
+	options, presetID, err := uc.Videos.resolveProcessing(userID, processing)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sizes := make([]int64, len(files))
+	for i, file := range files {
+		sizes[i] = file.Size
+	}
+	accepted, quotaErr := uc.Videos.Quota.CheckBatch(user, sizes)
+	if accepted == 0 {
+		return nil, nil, quotaErr
+	}
+
+	batch := entity.NewBatch(userID, strings.TrimSpace(name), len(files))
+	if err := uc.Repo.Create(batch); err != nil {
+		return nil, nil, err
+	}
+
+	results := make([]BatchItemResult, len(files))
+	for i, file := range files[accepted:] {
+		results[accepted+i] = BatchItemResult{FileName: file.FileName, Err: quotaErr}
+	}
+
+	slots := make(chan struct{}, max(uc.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i, file := range files[:accepted] {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			video, err := uc.uploadFile(userID, file, options, presetID, batch.ID)
+			results[i] = BatchItemResult{FileName: file.FileName, Video: video, Err: err}
+		}()
+	}
+	wg.Wait()
+
+	return batch, results, nil
+}
+
+func (uc *BatchUseCase) uploadFile(userID string, file BatchFile, options entity.ProcessingOptions, presetID, batchID string) (*entity.Video, error) {
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return uc.Videos.upload(userID, file.FileName, f, file.Size, options, presetID, &batchID)
+}
+
+func (uc *BatchUseCase) List(userID string) ([]entity.Batch, error) {
+	return uc.Repo.FindRecentByUserID(userID, recentBatchesLimit)
+}
+
+func (uc *BatchUseCase) Get(userID, batchID string) (*BatchDetail, error) {
+	batch, err := uc.Repo.FindByID(batchID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.UserID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	videos, err := uc.Videos.Repo.FindPage(repository.VideoFilter{
+		UserID:  userID,
+		BatchID: batch.ID,
+		Sort:    repository.SortCreatedAsc,
+		Limit:   MaxBatchFiles,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &BatchDetail{Batch: *batch, Videos: videos, StatusCounts: map[entity.VideoStatus]int{}, Finished: true}
+	for _, video := range videos {
+		detail.StatusCounts[video.Status]++
+		for _, status := range entity.InFlightStatuses {
+			if video.Status == status {
+				detail.Finished = false
+			}
+		}
+	}
+	return detail, nil
+}
@@ -0,0 +1,145 @@
+package usecase_test
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"mime/multipart"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func batchFile(name string, content []byte) usecase.BatchFile {
+	return usecase.BatchFile{
+		FileName: name,
+		Size:     int64(len(content)),
+		Open:     func() (multipart.File, error) { return videoFile(content), nil },
+	}
+}
+
+func TestBatchUseCase_Upload(t *testing.T) {
+	setup := func() (*usecase.BatchUseCase, *MockBatchRepository, *MockVideoRepository) {
+		batches, repo, userRepo, storage := new(MockBatchRepository), new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewBatchUseCase(batches, usecase.NewVideoUseCase(repo, userRepo, storage, nil))
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, ID: "u1", Email: "e@e.com"}, nil)
+		storage.On("GetBucketName").Return("bucket")
+		storage.On("UploadFile", mock.Anything, mock.Anything).Return(nil)
+		return uc, batches, repo
+	}
+
+	t.Run("Sucesso: Arquivo recusado não impede os demais", func(t *testing.T) {
+		uc, batches, repo := setup()
+		batches.On("Create", mock.MatchedBy(func(b *entity.Batch) bool {
+			return b.UserID == "u1" && b.Name == "aulas" && b.FileCount == 3
+		})).Return(nil)
+		repo.On("CreateWithOutbox", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.BatchID != nil && v.Options.FrameInterval == 5
+		}), mock.Anything).Return(nil)
+
+		batch, results, err := uc.Upload("u1", " aulas ", []usecase.BatchFile{
+			batchFile("a.mp4", mp4Header),
+			batchFile("b.pdf", []byte("%PDF-1.7")),
+			batchFile("c.mp4", mp4Header),
+		}, usecase.ProcessingRequest{Options: entity.ProcessingOptions{FrameInterval: 5}})
+
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, "a.mp4", results[0].FileName)
+		assert.Equal(t, batch.ID, *results[0].Video.BatchID)
+		assert.ErrorIs(t, results[1].Err, usecase.ErrUnsupportedFormat)
+		assert.Nil(t, results[1].Video)
+		assert.NoError(t, results[2].Err)
+		repo.AssertNumberOfCalls(t, "CreateWithOutbox", 2)
+	})
+
+	t.Run("Sucesso: Arquivos além da cota do plano são recusados sem envio", func(t *testing.T) {
+		uc, batches, repo := setup()
+		quota, _, _ := quotaWith(&entity.Plan{ID: "free", MaxVideosInFlight: 3, MaxUploadsPerHour: 10}, repository.VideoUsage{InFlight: 1})
+		uc.Videos.Quota = quota
+		batches.On("Create", mock.Anything).Return(nil)
+		repo.On("CreateWithOutbox", mock.Anything, mock.Anything).Return(nil)
+
+		files := make([]usecase.BatchFile, 5)
+		for i := range files {
+			files[i] = batchFile("v.mp4", mp4Header)
+		}
+		_, results, err := uc.Upload("u1", "", files, usecase.ProcessingRequest{})
+
+		assert.NoError(t, err)
+		assert.NoError(t, results[0].Err)
+		assert.NoError(t, results[1].Err)
+		for _, result := range results[2:] {
+			assert.ErrorIs(t, result.Err, usecase.ErrRateLimited)
+			assert.Nil(t, result.Video)
+		}
+		repo.AssertNumberOfCalls(t, "CreateWithOutbox", 2)
+	})
+
+	t.Run("Erro: Cota esgotada recusa o lote inteiro", func(t *testing.T) {
+		uc, batches, _ := setup()
+		quota, _, _ := quotaWith(&entity.Plan{ID: "free", MaxUploadsPerHour: 5}, repository.VideoUsage{UploadsSince: 5})
+		uc.Videos.Quota = quota
+
+		_, _, err := uc.Upload("u1", "", []usecase.BatchFile{batchFile("a.mp4", mp4Header)}, usecase.ProcessingRequest{})
+		assert.ErrorIs(t, err, usecase.ErrRateLimited)
+		batches.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Erro: Arquivos demais", func(t *testing.T) {
+		uc, batches, _ := setup()
+		files := make([]usecase.BatchFile, usecase.MaxBatchFiles+1)
+
+		_, _, err := uc.Upload("u1", "", files, usecase.ProcessingRequest{})
+		assert.ErrorIs(t, err, usecase.ErrInvalidBatch)
+		batches.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Erro: Opções inválidas recusam o lote inteiro", func(t *testing.T) {
+		uc, batches, _ := setup()
+
+		_, _, err := uc.Upload("u1", "", []usecase.BatchFile{batchFile("a.mp4", mp4Header)}, usecase.ProcessingRequest{
+			Options: entity.ProcessingOptions{OutputFormat: "gif"},
+		})
+		assert.ErrorIs(t, err, usecase.ErrInvalidOptions)
+		batches.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestBatchUseCase_Get(t *testing.T) {
+	t.Run("Sucesso: Conta os vídeos por status", func(t *testing.T) {
+		batches, repo := new(MockBatchRepository), new(MockVideoRepository)
+		uc := usecase.NewBatchUseCase(batches, usecase.NewVideoUseCase(repo, nil, nil, nil))
+		batches.On("FindByID", "b1").Return(&entity.Batch{ID: "b1", UserID: "u1"}, nil)
+		repo.On("FindPage", mock.MatchedBy(func(f repository.VideoFilter) bool {
+			return f.UserID == "u1" && f.BatchID == "b1"
+		})).Return([]entity.Video{{Status: entity.StatusDone}, {Status: entity.StatusDone}, {Status: entity.StatusError}}, nil)
+
+		detail, err := uc.Get("u1", "b1")
+		assert.NoError(t, err)
+		assert.Equal(t, 2, detail.StatusCounts[entity.StatusDone])
+		assert.Equal(t, 1, detail.StatusCounts[entity.StatusError])
+		assert.True(t, detail.Finished)
+	})
+
+	t.Run("Sucesso: Lote com vídeo pendente não terminou", func(t *testing.T) {
+		batches, repo := new(MockBatchRepository), new(MockVideoRepository)
+		uc := usecase.NewBatchUseCase(batches, usecase.NewVideoUseCase(repo, nil, nil, nil))
+		batches.On("FindByID", "b1").Return(&entity.Batch{ID: "b1", UserID: "u1"}, nil)
+		repo.On("FindPage", mock.Anything).Return([]entity.Video{{Status: entity.StatusDone}, {Status: entity.StatusPending}}, nil)
+
+		detail, err := uc.Get("u1", "b1")
+		assert.NoError(t, err)
+		assert.False(t, detail.Finished)
+	})
+
+	t.Run("Erro: Lote de outro usuário", func(t *testing.T) {
+		batches := new(MockBatchRepository)
+		uc := usecase.NewBatchUseCase(batches, usecase.NewVideoUseCase(nil, nil, nil, nil))
+		batches.On("FindByID", "b1").Return(&entity.Batch{ID: "b1", UserID: "u2"}, nil)
+
+		_, err := uc.Get("u1", "b1")
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+}
@@ -0,0 +1,113 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/auth"
+	"hackaton-service-api/internal/entity"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmailNotVerified         = errors.New("confirme o e-mail antes de enviar vídeos")
+	ErrEmailAlreadyVerified     = errors.New("e-mail já confirmado")
+	ErrInvalidVerificationToken = errors.New("link de confirmação inválido ou expirado")
+	ErrVerificationNotSent      = errors.New("não foi possível enviar o e-mail de confirmação")
+	ErrVerificationCooldown     = errors.New("aguarde antes de pedir outro e-mail de confirmação")
+)
+
+// Intervalo mínimo padrão entre dois e-mails de confirmação do mesmo usuário.
+const DefaultResendCooldown = time.Minute
+
+type VerificationTokens interface {
+	GenerateVerificationToken(userID, email string) (string, error)
+	ValidateVerificationToken(token string) (string, string, error)
+}
+
+// EmailMessage é uma mensagem de texto simples.
+type EmailMessage struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(message EmailMessage) error
+}
+
+// ResendVerification envia um novo link de confirmação; os anteriores
+// continuam valendo até expirar. Um novo envio só é aceito ResendCooldown
+// depois do anterior, contando o do cadastro.
+func (uc *UserUseCase) ResendVerification(userID string) error {
+	user, err := uc.Repo.FindByID(userID)
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return ErrEmailAlreadyVerified
+	}
+
+	now := time.Now()
+	marked, err := uc.Repo.MarkVerificationSent(user.ID, now, now.Add(-uc.ResendCooldown))
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrVerificationCooldown
+	}
+	user.VerificationSentAt = &now
+	return uc.sendVerification(user)
+}
+
+// VerifyEmail confirma o e-mail do token. O token guarda o e-mail para o qual
+// foi enviado, então deixa de valer se o usuário trocar de endereço. Confirmar
+// de novo um e-mail já confirmado não é erro.
+func (uc *UserUseCase) VerifyEmail(token string) (*entity.User, error) {
+	if uc.Verification == nil {
+		return nil, ErrInvalidVerificationToken
+	}
+	userID, email, err := uc.Verification.ValidateVerificationToken(token)
+	if err != nil {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := uc.Repo.FindByID(userID)
+	if err != nil || !strings.EqualFold(user.Email, email) {
+		return nil, ErrInvalidVerificationToken
+	}
+	if user.IsEmailVerified() {
+		return user, nil
+	}
+
+	user.VerifyEmail()
+	if err := uc.Repo.Update(user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// sendVerification não faz nada sem Verification ou Mailer configurados.
+func (uc *UserUseCase) sendVerification(user *entity.User) error {
+	if uc.Verification == nil || uc.Mailer == nil {
+		return nil
+	}
+
+	token, err := uc.Verification.GenerateVerificationToken(user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationNotSent, err)
+	}
+	link := uc.VerifyURL + "?token=" + url.QueryEscape(token)
+
+	err = uc.Mailer.Send(EmailMessage{
+		To:      user.Email,
+		Subject: "Confirme seu e-mail",
+		Body: fmt.Sprintf("Olá, %s!\n\nPara começar a enviar vídeos, confirme seu e-mail abrindo o link abaixo:\n\n%s\n\nO link vale por %d horas. Se você não criou esta conta, ignore esta mensagem.\n",
+			user.Username, link, int(auth.EmailVerificationTTL.Hours())),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrVerificationNotSent, err)
+	}
+	return nil
+}
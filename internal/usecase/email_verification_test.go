@@ -0,0 +1,129 @@
+package usecase_test
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/usecase"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserUseCase_Register_SendsVerification(t *testing.T) {
+	setup := func() (*usecase.UserUseCase, *MockVerificationTokens, *MockMailer) {
+		repo, tokens, mailer := new(MockUserRepository), new(MockVerificationTokens), new(MockMailer)
+		uc := usecase.NewUserUseCase(repo, nil, nil)
+		uc.Verification, uc.Mailer, uc.VerifyURL = tokens, mailer, "http://api/api/verify-email"
+		repo.On("FindByUsername", "ana").Return(nil, nil)
+		repo.On("FindByEmail", "ana@a.com").Return(nil, nil)
+		repo.On("Create", mock.Anything).Return(nil)
+		tokens.On("GenerateVerificationToken", mock.Anything, "ana@a.com").Return("tok+1", nil)
+		return uc, tokens, mailer
+	}
+
+	t.Run("Sucesso: Envia o link com o token", func(t *testing.T) {
+		uc, _, mailer := setup()
+		mailer.On("Send", mock.MatchedBy(func(m usecase.EmailMessage) bool {
+			return m.To == "ana@a.com" && strings.Contains(m.Body, "http://api/api/verify-email?token=tok%2B1")
+		})).Return(nil)
+
+		assert.NoError(t, uc.Register("ana", "ana@a.com", "123456"))
+		mailer.AssertExpectations(t)
+		uc.Repo.(*MockUserRepository).AssertCalled(t, "Create", mock.MatchedBy(func(u *entity.User) bool {
+			return u.VerificationSentAt != nil
+		}))
+	})
+
+	t.Run("Erro: Falha no envio mantém a conta", func(t *testing.T) {
+		uc, _, mailer := setup()
+		mailer.On("Send", mock.Anything).Return(errors.New("smtp fora"))
+
+		err := uc.Register("ana", "ana@a.com", "123456")
+		assert.ErrorIs(t, err, usecase.ErrVerificationNotSent)
+		uc.Repo.(*MockUserRepository).AssertCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestUserUseCase_VerifyEmail(t *testing.T) {
+	setup := func(user *entity.User) (*usecase.UserUseCase, *MockUserRepository) {
+		repo, tokens := new(MockUserRepository), new(MockVerificationTokens)
+		uc := usecase.NewUserUseCase(repo, nil, nil)
+		uc.Verification = tokens
+		tokens.On("ValidateVerificationToken", "tok").Return("u1", "ana@a.com", nil)
+		tokens.On("ValidateVerificationToken", "ruim").Return("", "", errors.New("assinatura"))
+		repo.On("FindByID", "u1").Return(user, nil)
+		return uc, repo
+	}
+
+	t.Run("Sucesso: Marca o e-mail como confirmado", func(t *testing.T) {
+		uc, repo := setup(&entity.User{ID: "u1", Email: "Ana@a.com"})
+		repo.On("Update", mock.MatchedBy(func(u *entity.User) bool { return u.IsEmailVerified() })).Return(nil)
+
+		user, err := uc.VerifyEmail("tok")
+		require.NoError(t, err)
+		assert.True(t, user.IsEmailVerified())
+	})
+
+	t.Run("Sucesso: Já confirmado não grava de novo", func(t *testing.T) {
+		uc, repo := setup(&entity.User{ID: "u1", Email: "ana@a.com", EmailVerifiedAt: &verifiedAt})
+
+		_, err := uc.VerifyEmail("tok")
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Erro: E-mail trocado depois do envio", func(t *testing.T) {
+		uc, _ := setup(&entity.User{ID: "u1", Email: "outro@a.com"})
+
+		_, err := uc.VerifyEmail("tok")
+		assert.ErrorIs(t, err, usecase.ErrInvalidVerificationToken)
+	})
+
+	t.Run("Erro: Token inválido", func(t *testing.T) {
+		uc, _ := setup(&entity.User{ID: "u1", Email: "ana@a.com"})
+
+		_, err := uc.VerifyEmail("ruim")
+		assert.ErrorIs(t, err, usecase.ErrInvalidVerificationToken)
+	})
+}
+
+func TestUserUseCase_ResendVerification(t *testing.T) {
+	setup := func(user *entity.User) (*usecase.UserUseCase, *MockUserRepository, *MockMailer) {
+		repo, tokens, mailer := new(MockUserRepository), new(MockVerificationTokens), new(MockMailer)
+		uc := usecase.NewUserUseCase(repo, nil, nil)
+		uc.Verification, uc.Mailer, uc.VerifyURL = tokens, mailer, "http://api/api/verify-email"
+		repo.On("FindByID", "u1").Return(user, nil)
+		tokens.On("GenerateVerificationToken", "u1", "ana@a.com").Return("tok", nil)
+		return uc, repo, mailer
+	}
+
+	t.Run("Erro: E-mail já confirmado", func(t *testing.T) {
+		uc, repo, _ := setup(&entity.User{ID: "u1", Email: "ana@a.com", EmailVerifiedAt: &verifiedAt})
+
+		assert.ErrorIs(t, uc.ResendVerification("u1"), usecase.ErrEmailAlreadyVerified)
+		repo.AssertNotCalled(t, "MarkVerificationSent", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Envia depois do intervalo mínimo", func(t *testing.T) {
+		uc, repo, mailer := setup(&entity.User{ID: "u1", Email: "ana@a.com"})
+		repo.On("MarkVerificationSent", "u1", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= time.Minute && time.Since(before) < time.Minute+time.Second
+		})).Return(true, nil)
+		mailer.On("Send", mock.Anything).Return(nil)
+
+		assert.NoError(t, uc.ResendVerification("u1"))
+		mailer.AssertExpectations(t)
+	})
+
+	t.Run("Erro: Reenvio dentro do intervalo mínimo", func(t *testing.T) {
+		uc, repo, mailer := setup(&entity.User{ID: "u1", Email: "ana@a.com"})
+		repo.On("MarkVerificationSent", "u1", mock.Anything, mock.Anything).Return(false, nil)
+
+		assert.ErrorIs(t, uc.ResendVerification("u1"), usecase.ErrVerificationCooldown)
+		mailer.AssertNotCalled(t, "Send", mock.Anything)
+	})
+}
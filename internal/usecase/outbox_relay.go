@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"time"
+)
+
+// OutboxRelay publica na fila as mensagens gravadas no outbox. Falhas não
+// descartam a mensagem: ela é tentada de novo com backoff exponencial até a
+// fila aceitar.
+type OutboxRelay struct {
+	Repo        repository.OutboxRepository
+	Queue       QueueService
+	BatchSize   int
+	Lease       time.Duration
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func NewOutboxRelay(repo repository.OutboxRepository, queue QueueService) *OutboxRelay {
+	return &OutboxRelay{
+		Repo:        repo,
+		Queue:       queue,
+		BatchSize:   100,
+		Lease:       time.Minute,
+		BaseBackoff: 5 * time.Second,
+		MaxBackoff:  5 * time.Minute,
+	}
+}
+
+// Relay publica um lote de mensagens pendentes e devolve quantas foram
+// enviadas.
+func (r *OutboxRelay) Relay() (int, error) {
+	messages, err := r.Repo.ClaimDue(time.Now(), r.Lease, r.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for i := range messages {
+		message := &messages[i]
+		if err := r.publish(message); err != nil {
+			errs = append(errs, fmt.Errorf("mensagem %s: %w", message.ID, err))
+		} else {
+			sent++
+		}
+
+		if err := r.Repo.Update(message); err != nil {
+			errs = append(errs, fmt.Errorf("mensagem %s: %w", message.ID, err))
+		}
+	}
+
+	return sent, errors.Join(errs...)
+}
+
+// Cleanup remove as mensagens já enviadas há mais de retention.
+func (r *OutboxRelay) Cleanup(retention time.Duration) (int64, error) {
+	return r.Repo.DeleteSentBefore(time.Now().Add(-retention))
+}
+
+func (r *OutboxRelay) publish(message *entity.OutboxMessage) error {
+	message.Attempts++
+
+	payload, err := message.VideoMessage()
+	if err == nil {
+		err = r.Queue.SendMessage(payload)
+	}
+	if err != nil {
+		message.LastError = err.Error()
+		message.NextAttemptAt = time.Now().Add(r.backoff(message.Attempts))
+		return err
+	}
+
+	now := time.Now()
+	message.SentAt = &now
+	message.LastError = ""
+	return nil
+}
+
+func (r *OutboxRelay) backoff(attempts int) time.Duration {
+	delay := r.BaseBackoff
+	for i := 1; i < attempts && delay < r.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, r.MaxBackoff)
+}
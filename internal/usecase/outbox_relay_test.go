@@ -0,0 +1,80 @@
+package usecase_test
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	t.Run("Sucesso: Mensagem publicada é marcada como enviada", func(t *testing.T) {
+		repo, queue := new(MockOutboxRepository), new(MockQueueService)
+		relay := usecase.NewOutboxRelay(repo, queue)
+		message := entity.NewVideoOutboxMessage(entity.NewVideoMessage(&entity.Video{ID: "v1"}, "e@e.com"))
+
+		repo.On("ClaimDue", mock.Anything, time.Minute, 100).Return([]entity.OutboxMessage{*message}, nil)
+		queue.On("SendMessage", videoMessage("v1", "e@e.com")).Return(nil)
+		repo.On("Update", mock.MatchedBy(func(m *entity.OutboxMessage) bool {
+			return m.SentAt != nil && m.Attempts == 1 && m.LastError == ""
+		})).Return(nil)
+
+		sent, err := relay.Relay()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sent)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Erro: Falha na fila reagenda com backoff", func(t *testing.T) {
+		repo, queue := new(MockOutboxRepository), new(MockQueueService)
+		relay := usecase.NewOutboxRelay(repo, queue)
+		message := entity.NewVideoOutboxMessage(entity.NewVideoMessage(&entity.Video{ID: "v1"}, "e@e.com"))
+		message.Attempts = 2
+		before := time.Now()
+
+		repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).Return([]entity.OutboxMessage{*message}, nil)
+		queue.On("SendMessage", videoMessage("v1", "e@e.com")).Return(errors.New("sqs fail"))
+		repo.On("Update", mock.MatchedBy(func(m *entity.OutboxMessage) bool {
+			delay := m.NextAttemptAt.Sub(before)
+			return m.SentAt == nil && m.Attempts == 3 && m.LastError == "sqs fail" &&
+				delay >= 20*time.Second && delay < 21*time.Second
+		})).Return(nil)
+
+		sent, err := relay.Relay()
+		assert.ErrorContains(t, err, "sqs fail")
+		assert.Equal(t, 0, sent)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Sucesso: Backoff limitado ao máximo", func(t *testing.T) {
+		repo, queue := new(MockOutboxRepository), new(MockQueueService)
+		relay := usecase.NewOutboxRelay(repo, queue)
+		message := entity.NewVideoOutboxMessage(entity.NewVideoMessage(&entity.Video{ID: "v1"}, "e@e.com"))
+		message.Attempts = 40
+		before := time.Now()
+
+		repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).Return([]entity.OutboxMessage{*message}, nil)
+		queue.On("SendMessage", videoMessage("v1", "e@e.com")).Return(errors.New("sqs fail"))
+		repo.On("Update", mock.MatchedBy(func(m *entity.OutboxMessage) bool {
+			delay := m.NextAttemptAt.Sub(before)
+			return delay >= 5*time.Minute && delay < 5*time.Minute+time.Second
+		})).Return(nil)
+
+		_, err := relay.Relay()
+		assert.Error(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Erro: Falha ao buscar mensagens", func(t *testing.T) {
+		repo := new(MockOutboxRepository)
+		relay := usecase.NewOutboxRelay(repo, nil)
+		repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything).Return([]entity.OutboxMessage(nil), errors.New("db fail"))
+
+		_, err := relay.Relay()
+		assert.EqualError(t, err, "db fail")
+	})
+}
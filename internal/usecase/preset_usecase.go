@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"strings"
+	"unicode/utf8"
+)
+
+var ErrPresetNameTaken = errors.New("já existe um preset com esse nome")
+
+const maxPresetNameLength = 100
+
+// PresetInput é o que o usuário informa ao criar ou editar um preset.
+type PresetInput struct {
+	Name      string
+	Options   entity.ProcessingOptions
+	IsDefault bool
+}
+
+type PresetUseCase struct {
+	Repo repository.PresetRepository
+}
+
+func NewPresetUseCase(repo repository.PresetRepository) *PresetUseCase {
+	return &PresetUseCase{Repo: repo}
+}
+
+// Create grava o preset com as opções completas: campos omitidos recebem o
+// padrão do sistema no momento da criação.
+func (uc *PresetUseCase) Create(userID string, input PresetInput) (*entity.Preset, error) {
+	name, options, err := uc.validate(userID, "", input)
+	if err != nil {
+		return nil, err
+	}
+
+	preset := entity.NewPreset(userID, name, options)
+	preset.IsDefault = input.IsDefault
+	if err := uc.Repo.Create(preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+func (uc *PresetUseCase) List(userID string) ([]entity.Preset, error) {
+	return uc.Repo.FindAllByUserID(userID)
+}
+
+func (uc *PresetUseCase) Get(userID, presetID string) (*entity.Preset, error) {
+	return findOwnedPreset(uc.Repo, userID, presetID)
+}
+
+func (uc *PresetUseCase) Update(userID, presetID string, input PresetInput) (*entity.Preset, error) {
+	preset, err := findOwnedPreset(uc.Repo, userID, presetID)
+	if err != nil {
+		return nil, err
+	}
+
+	name, options, err := uc.validate(userID, preset.ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	preset.Name = name
+	preset.Options = options
+	preset.IsDefault = input.IsDefault
+	if err := uc.Repo.Update(preset); err != nil {
+		return nil, err
+	}
+	return preset, nil
+}
+
+func (uc *PresetUseCase) Delete(userID, presetID string) error {
+	preset, err := findOwnedPreset(uc.Repo, userID, presetID)
+	if err != nil {
+		return err
+	}
+	return uc.Repo.Delete(preset)
+}
+
+func (uc *PresetUseCase) validate(userID, presetID string, input PresetInput) (string, entity.ProcessingOptions, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxPresetNameLength {
+		return "", entity.ProcessingOptions{}, fmt.Errorf("%w: nome deve ter entre 1 e %d caracteres", ErrInvalidOptions, maxPresetNameLength)
+	}
+
+	options, err := resolveOptions(input.Options)
+	if err != nil {
+		return "", entity.ProcessingOptions{}, err
+	}
+
+	existing, err := uc.Repo.FindByName(userID, name)
+	switch {
+	case err == nil && existing.ID != presetID:
+		return "", entity.ProcessingOptions{}, ErrPresetNameTaken
+	case err != nil && !errors.Is(err, repository.ErrNotFound):
+		return "", entity.ProcessingOptions{}, err
+	}
+
+	return name, options, nil
+}
+
+func findOwnedPreset(repo repository.PresetRepository, userID, presetID string) (*entity.Preset, error) {
+	preset, err := repo.FindByID(presetID)
+	if err != nil {
+		return nil, err
+	}
+	if preset.UserID != userID {
+		return nil, ErrAccessDenied
+	}
+	return preset, nil
+}
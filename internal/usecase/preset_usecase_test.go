@@ -0,0 +1,153 @@
+package usecase_test
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPresetUseCase_Create(t *testing.T) {
+	t.Run("Erro: Nome vazio", func(t *testing.T) {
+		uc := usecase.NewPresetUseCase(new(MockPresetRepository))
+		_, err := uc.Create("u1", usecase.PresetInput{Name: "  "})
+		assert.ErrorIs(t, err, usecase.ErrInvalidOptions)
+	})
+
+	t.Run("Erro: Opções inválidas", func(t *testing.T) {
+		uc := usecase.NewPresetUseCase(new(MockPresetRepository))
+		_, err := uc.Create("u1", usecase.PresetInput{Name: "Rápido", Options: entity.ProcessingOptions{OutputFormat: "bmp"}})
+		assert.ErrorIs(t, err, usecase.ErrInvalidOptions)
+	})
+
+	t.Run("Erro: Nome já usado", func(t *testing.T) {
+		repo := new(MockPresetRepository)
+		uc := usecase.NewPresetUseCase(repo)
+		repo.On("FindByName", "u1", "Rápido").Return(&entity.Preset{ID: "p1", UserID: "u1"}, nil)
+
+		_, err := uc.Create("u1", usecase.PresetInput{Name: "Rápido"})
+		assert.ErrorIs(t, err, usecase.ErrPresetNameTaken)
+	})
+
+	t.Run("Sucesso: Opções omitidas recebem o padrão", func(t *testing.T) {
+		repo := new(MockPresetRepository)
+		uc := usecase.NewPresetUseCase(repo)
+		repo.On("FindByName", "u1", "Rápido").Return(nil, repository.ErrNotFound)
+		repo.On("Create", mock.Anything).Return(nil)
+
+		preset, err := uc.Create("u1", usecase.PresetInput{Name: " Rápido ", Options: entity.ProcessingOptions{FrameInterval: 5}, IsDefault: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "Rápido", preset.Name)
+		assert.Equal(t, entity.ProcessingOptions{FrameInterval: 5, OutputFormat: "png"}, preset.Options)
+		assert.True(t, preset.IsDefault)
+	})
+}
+
+func TestPresetUseCase_Update(t *testing.T) {
+	t.Run("Erro: Preset de outro usuário", func(t *testing.T) {
+		repo := new(MockPresetRepository)
+		uc := usecase.NewPresetUseCase(repo)
+		repo.On("FindByID", "p1").Return(&entity.Preset{ID: "p1", UserID: "u2"}, nil)
+
+		_, err := uc.Update("u1", "p1", usecase.PresetInput{Name: "Rápido"})
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+
+	t.Run("Sucesso: Mantém o próprio nome", func(t *testing.T) {
+		repo := new(MockPresetRepository)
+		uc := usecase.NewPresetUseCase(repo)
+		preset := &entity.Preset{ID: "p1", UserID: "u1", Name: "Rápido"}
+		repo.On("FindByID", "p1").Return(preset, nil)
+		repo.On("FindByName", "u1", "Rápido").Return(preset, nil)
+		repo.On("Update", preset).Return(nil)
+
+		updated, err := uc.Update("u1", "p1", usecase.PresetInput{Name: "Rápido", Options: entity.ProcessingOptions{OutputFormat: "jpg"}})
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", updated.Options.OutputFormat)
+	})
+}
+
+func TestVideoUseCase_RequestUpload_Presets(t *testing.T) {
+	preset := &entity.Preset{ID: "p1", UserID: "u1", Options: entity.ProcessingOptions{FrameInterval: 10, OutputFormat: "jpeg", MaxFrames: 50}}
+
+	setup := func() (*usecase.VideoUseCase, *MockVideoRepository, *MockPresetRepository) {
+		repo, userRepo, storage, presets := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService), new(MockPresetRepository)
+		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
+		uc.Presets = presets
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, ID: "u1", Email: "e@e.com"}, nil)
+		storage.On("GetBucketName").Return("bucket")
+		storage.On("UploadFile", mock.Anything, mock.Anything).Return(nil)
+		repo.On("CreateWithOutbox", mock.Anything, mock.Anything).Return(nil)
+		return uc, repo, presets
+	}
+
+	t.Run("Sucesso: Opções informadas sobrepõem o preset", func(t *testing.T) {
+		uc, _, presets := setup()
+		presets.On("FindByID", "p1").Return(preset, nil)
+
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{
+			PresetID: "p1",
+			Options:  entity.ProcessingOptions{FrameInterval: 2},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, entity.ProcessingOptions{FrameInterval: 2, OutputFormat: "jpeg", MaxFrames: 50}, video.Options)
+		assert.Equal(t, "p1", video.PresetID)
+	})
+
+	t.Run("Sucesso: max_frames=0 informado tira o limite do preset", func(t *testing.T) {
+		uc, _, presets := setup()
+		presets.On("FindByID", "p3").Return(&entity.Preset{ID: "p3", UserID: "u1", Options: entity.ProcessingOptions{MaxFrames: 100}}, nil)
+
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{
+			PresetID:     "p3",
+			Options:      entity.ProcessingOptions{MaxFrames: 0},
+			MaxFramesSet: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, video.Options.MaxFrames)
+	})
+
+	t.Run("Sucesso: max_frames omitido mantém o limite do preset", func(t *testing.T) {
+		uc, _, presets := setup()
+		presets.On("FindByID", "p3").Return(&entity.Preset{ID: "p3", UserID: "u1", Options: entity.ProcessingOptions{MaxFrames: 100}}, nil)
+
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{PresetID: "p3"})
+		assert.NoError(t, err)
+		assert.Equal(t, 100, video.Options.MaxFrames)
+	})
+
+	t.Run("Sucesso: Sem preset_id usa o preset padrão", func(t *testing.T) {
+		uc, repo, presets := setup()
+		presets.On("FindDefault", "u1").Return(preset, nil)
+
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, preset.Options, video.Options)
+		repo.AssertCalled(t, "CreateWithOutbox", mock.Anything, mock.MatchedBy(func(msg *entity.OutboxMessage) bool {
+			message, err := msg.VideoMessage()
+			return err == nil && message.Options == preset.Options
+		}))
+	})
+
+	t.Run("Sucesso: Sem preset padrão usa o padrão do sistema", func(t *testing.T) {
+		uc, _, presets := setup()
+		presets.On("FindDefault", "u1").Return(nil, repository.ErrNotFound)
+
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, entity.DefaultProcessingOptions(), video.Options)
+		assert.Empty(t, video.PresetID)
+	})
+
+	t.Run("Erro: Preset de outro usuário", func(t *testing.T) {
+		uc, repo, presets := setup()
+		presets.On("FindByID", "p2").Return(&entity.Preset{ID: "p2", UserID: "u2"}, nil)
+
+		_, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{PresetID: "p2"})
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+		repo.AssertNotCalled(t, "CreateWithOutbox", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"strings"
+)
+
+var ErrInvalidOptions = errors.New("opções de processamento inválidas")
+
+// Limites das opções de processamento aceitas no upload.
+const (
+	MinFrameInterval = 0.1
+	MaxFrameInterval = 3600.0
+	MaxFramesLimit   = 10000
+)
+
+// ValidateProcessingOptions confere as opções informadas. Campos zerados são
+// aceitos porque recebem o valor padrão.
+func ValidateProcessingOptions(options entity.ProcessingOptions) error {
+	if options.FrameInterval != 0 && (options.FrameInterval < MinFrameInterval || options.FrameInterval > MaxFrameInterval) {
+		return fmt.Errorf("%w: intervalo entre frames deve estar entre %g e %g segundos", ErrInvalidOptions, MinFrameInterval, MaxFrameInterval)
+	}
+	switch options.OutputFormat {
+	case "", entity.FrameFormatPNG, entity.FrameFormatJPEG:
+	default:
+		return fmt.Errorf("%w: formato de saída %q não suportado (use %s ou %s)", ErrInvalidOptions, options.OutputFormat, entity.FrameFormatPNG, entity.FrameFormatJPEG)
+	}
+	if options.MaxFrames < 0 || options.MaxFrames > MaxFramesLimit {
+		return fmt.Errorf("%w: max_frames deve estar entre 0 (sem limite) e %d", ErrInvalidOptions, MaxFramesLimit)
+	}
+	return nil
+}
+
+// ProcessingRequest são as opções pedidas no upload: um preset do usuário
+// (ou, sem PresetID, o preset padrão dele) com os campos informados em
+// Options sobrepostos. Em Options.MaxFrames zero é tanto "não informado"
+// quanto "sem limite"; MaxFramesSet indica que foi informado, e então o valor
+// vale mesmo que o preset tenha um limite.
+type ProcessingRequest struct {
+	PresetID     string
+	Options      entity.ProcessingOptions
+	MaxFramesSet bool
+}
+
+// resolveOptions normaliza e valida as opções pedidas e completa o que não
+// foi informado com o padrão do sistema.
+func resolveOptions(requested entity.ProcessingOptions) (entity.ProcessingOptions, error) {
+	options, err := normalizeOptions(requested)
+	if err != nil {
+		return entity.ProcessingOptions{}, err
+	}
+	return options.WithDefaults(entity.DefaultProcessingOptions()), nil
+}
+
+func normalizeOptions(options entity.ProcessingOptions) (entity.ProcessingOptions, error) {
+	options.OutputFormat = strings.ToLower(strings.TrimSpace(options.OutputFormat))
+	if options.OutputFormat == "jpg" {
+		options.OutputFormat = entity.FrameFormatJPEG
+	}
+
+	if err := ValidateProcessingOptions(options); err != nil {
+		return entity.ProcessingOptions{}, err
+	}
+	return options, nil
+}
+
+// resolveProcessing devolve as opções que ficam gravadas no vídeo e o preset
+// de onde vieram (vazio quando nenhum foi usado). Sem repositório de presets,
+// só as opções informadas e o padrão do sistema valem.
+func (uc *VideoUseCase) resolveProcessing(userID string, request ProcessingRequest) (entity.ProcessingOptions, string, error) {
+	options, err := normalizeOptions(request.Options)
+	if err != nil {
+		return entity.ProcessingOptions{}, "", err
+	}
+
+	var preset *entity.Preset
+	switch {
+	case request.PresetID != "":
+		if uc.Presets == nil {
+			return entity.ProcessingOptions{}, "", repository.ErrNotFound
+		}
+		preset, err = findOwnedPreset(uc.Presets, userID, request.PresetID)
+		if err != nil {
+			return entity.ProcessingOptions{}, "", err
+		}
+	case uc.Presets != nil:
+		preset, err = uc.Presets.FindDefault(userID)
+		if errors.Is(err, repository.ErrNotFound) {
+			preset, err = nil, nil
+		}
+		if err != nil {
+			return entity.ProcessingOptions{}, "", err
+		}
+	}
+
+	presetID := ""
+	if preset != nil {
+		maxFrames := options.MaxFrames
+		options = options.WithDefaults(preset.Options)
+		if request.MaxFramesSet {
+			options.MaxFrames = maxFrames
+		}
+		presetID = preset.ID
+	}
+	return options.WithDefaults(entity.DefaultProcessingOptions()), presetID, nil
+}
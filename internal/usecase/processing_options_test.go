@@ -0,0 +1,48 @@
+package usecase_test
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateProcessingOptions(t *testing.T) {
+	valid := []entity.ProcessingOptions{
+		{},
+		{FrameInterval: 0.1, OutputFormat: "png", MaxFrames: 10000},
+		{FrameInterval: 3600, OutputFormat: "jpeg"},
+	}
+	for _, options := range valid {
+		assert.NoError(t, usecase.ValidateProcessingOptions(options), "%+v", options)
+	}
+
+	invalid := []entity.ProcessingOptions{
+		{FrameInterval: 0.01},
+		{FrameInterval: -1},
+		{FrameInterval: 7200},
+		{OutputFormat: "gif"},
+		{MaxFrames: -1},
+		{MaxFrames: 10001},
+	}
+	for _, options := range invalid {
+		assert.ErrorIs(t, usecase.ValidateProcessingOptions(options), usecase.ErrInvalidOptions, "%+v", options)
+	}
+}
+
+func TestNewVideoMessage(t *testing.T) {
+	video := &entity.Video{ID: "v1", UserID: "u1", FileName: "v.mp4", InputBucket: "b", InputKey: "uploads/v.mp4", CorrelationID: "c1"}
+
+	message := entity.NewVideoMessage(video, "e@e.com")
+	assert.Equal(t, entity.VideoMessageSchemaVersion, message.SchemaVersion)
+	assert.NotEmpty(t, message.MessageID)
+	assert.Equal(t, entity.DefaultProcessingOptions(), message.Options, "vídeos antigos recebem as opções padrão")
+	assert.Equal(t, map[string]string{
+		"schema_version": "2",
+		"message_id":     message.MessageID,
+		"correlation_id": "c1",
+		"video_id":       "v1",
+		"output_format":  "png",
+	}, message.Attributes())
+}
@@ -0,0 +1,189 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"time"
+)
+
+var (
+	ErrQuotaExceeded = errors.New("cota de armazenamento excedida")
+	ErrRateLimited   = errors.New("limite de envios atingido")
+)
+
+// UploadRateWindow é a janela do limite de envios por hora do plano.
+const UploadRateWindow = time.Hour
+
+// UsageMetric compara o consumo com o limite do plano; Limit zero é ilimitado.
+type UsageMetric struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"`
+}
+
+func (m UsageMetric) exceeds(additional int64) bool {
+	return m.Limit > 0 && m.Used+additional > m.Limit
+}
+
+type Usage struct {
+	Plan            string      `json:"plan"`
+	StorageBytes    UsageMetric `json:"storage_bytes"`
+	VideosInFlight  UsageMetric `json:"videos_in_flight"`
+	UploadsLastHour UsageMetric `json:"uploads_last_hour"`
+}
+
+// QuotaService aplica as cotas do plano do usuário antes de qualquer escrita
+// no S3. A verificação não é atômica: envios simultâneos podem ultrapassar o
+// limite em alguns vídeos, o que é aceitável para controle de custo. Um
+// QuotaService nil não impõe limites.
+type QuotaService struct {
+	Plans    repository.PlanRepository
+	UserRepo repository.UserRepository
+	Videos   repository.VideoRepository
+}
+
+func NewQuotaService(plans repository.PlanRepository, userRepo repository.UserRepository, videos repository.VideoRepository) *QuotaService {
+	return &QuotaService{Plans: plans, UserRepo: userRepo, Videos: videos}
+}
+
+// UsageFor é o consumo exibido em GET /api/me/usage.
+func (q *QuotaService) UsageFor(userID string) (*Usage, error) {
+	user, err := q.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("usuário não encontrado")
+	}
+	return q.usage(user)
+}
+
+// CheckUpload verifica se um novo vídeo de size bytes cabe no plano. Use
+// size zero quando o tamanho ainda não é conhecido (upload em partes).
+func (q *QuotaService) CheckUpload(user *entity.User, size int64) error {
+	if q == nil {
+		return nil
+	}
+
+	usage, err := q.usage(user)
+	if err != nil {
+		return err
+	}
+
+	if usage.VideosInFlight.exceeds(1) {
+		return fmt.Errorf("%w: máximo de %d vídeos em andamento", ErrRateLimited, usage.VideosInFlight.Limit)
+	}
+	if usage.UploadsLastHour.exceeds(1) {
+		return fmt.Errorf("%w: máximo de %d envios por hora", ErrRateLimited, usage.UploadsLastHour.Limit)
+	}
+	return checkStorage(usage, size)
+}
+
+// CheckBatch verifica um lote de uma vez: os vídeos só passam a contar no
+// consumo depois de gravados, então conferir arquivo por arquivo deixaria o
+// lote inteiro passar pelos limites. Devolve quantos dos primeiros vídeos, com
+// os tamanhos em sizes, cabem no plano e, quando não cabem todos, o erro do
+// limite atingido.
+func (q *QuotaService) CheckBatch(user *entity.User, sizes []int64) (int, error) {
+	if q == nil {
+		return len(sizes), nil
+	}
+
+	usage, err := q.usage(user)
+	if err != nil {
+		return 0, err
+	}
+
+	accepted, limitErr := len(sizes), error(nil)
+	if m := usage.VideosInFlight; m.Limit > 0 && m.Limit-m.Used < int64(accepted) {
+		accepted = int(max(m.Limit-m.Used, 0))
+		limitErr = fmt.Errorf("%w: máximo de %d vídeos em andamento", ErrRateLimited, m.Limit)
+	}
+	if m := usage.UploadsLastHour; m.Limit > 0 && m.Limit-m.Used < int64(accepted) {
+		accepted = int(max(m.Limit-m.Used, 0))
+		limitErr = fmt.Errorf("%w: máximo de %d envios por hora", ErrRateLimited, m.Limit)
+	}
+
+	var total int64
+	for i, size := range sizes[:accepted] {
+		total += size
+		if err := checkStorage(usage, total); err != nil {
+			return i, err
+		}
+	}
+	return accepted, limitErr
+}
+
+// CheckInFlight verifica só o limite de vídeos em andamento, para um vídeo que
+// volta ao pipeline sem novo envio (reprocessamento).
+func (q *QuotaService) CheckInFlight(user *entity.User) error {
+	if q == nil {
+		return nil
+	}
+
+	usage, err := q.usage(user)
+	if err != nil {
+		return err
+	}
+
+	if usage.VideosInFlight.exceeds(1) {
+		return fmt.Errorf("%w: máximo de %d vídeos em andamento", ErrRateLimited, usage.VideosInFlight.Limit)
+	}
+	return nil
+}
+
+// CheckStorage confere só o espaço, para partes de um upload que já foi
+// contado como envio e como vídeo em andamento.
+func (q *QuotaService) CheckStorage(user *entity.User, size int64) error {
+	if q == nil {
+		return nil
+	}
+
+	usage, err := q.usage(user)
+	if err != nil {
+		return err
+	}
+	return checkStorage(usage, size)
+}
+
+func checkStorage(usage *Usage, size int64) error {
+	if usage.StorageBytes.exceeds(size) {
+		return fmt.Errorf("%w: %d de %d MB em uso", ErrQuotaExceeded, usage.StorageBytes.Used>>20, usage.StorageBytes.Limit>>20)
+	}
+	return nil
+}
+
+func (q *QuotaService) usage(user *entity.User) (*Usage, error) {
+	plan, err := q.planFor(user)
+	if err != nil {
+		return nil, err
+	}
+
+	consumed, err := q.Videos.Usage(user.ID, time.Now().Add(-UploadRateWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Usage{
+		Plan:            plan.ID,
+		StorageBytes:    UsageMetric{Used: consumed.StoredBytes, Limit: plan.MaxStorageBytes},
+		VideosInFlight:  UsageMetric{Used: consumed.InFlight, Limit: plan.MaxVideosInFlight},
+		UploadsLastHour: UsageMetric{Used: consumed.UploadsSince, Limit: plan.MaxUploadsPerHour},
+	}, nil
+}
+
+// planFor cai no plano padrão quando o plano do usuário não existe mais,
+// para não liberar cota ilimitada por um erro de cadastro.
+func (q *QuotaService) planFor(user *entity.User) (*entity.Plan, error) {
+	planID := user.PlanID
+	if planID == "" {
+		planID = entity.DefaultPlanID
+	}
+
+	plan, err := q.Plans.FindByID(planID)
+	if errors.Is(err, repository.ErrNotFound) && planID != entity.DefaultPlanID {
+		plan, err = q.Plans.FindByID(entity.DefaultPlanID)
+	}
+	if errors.Is(err, repository.ErrNotFound) {
+		return entity.DefaultPlan(), nil
+	}
+	return plan, err
+}
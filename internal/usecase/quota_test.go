@@ -0,0 +1,139 @@
+package usecase_test
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func quotaWith(plan *entity.Plan, usage repository.VideoUsage) (*usecase.QuotaService, *MockPlanRepository, *MockVideoRepository) {
+	plans, videos := new(MockPlanRepository), new(MockVideoRepository)
+	plans.On("FindByID", plan.ID).Return(plan, nil)
+	videos.On("Usage", "u1", mock.Anything).Return(&usage, nil)
+	return usecase.NewQuotaService(plans, nil, videos), plans, videos
+}
+
+func TestQuotaService_CheckUpload(t *testing.T) {
+	plan := &entity.Plan{ID: "free", MaxStorageBytes: 100 << 20, MaxVideosInFlight: 2, MaxUploadsPerHour: 5}
+	user := &entity.User{ID: "u1", PlanID: "free"}
+
+	t.Run("Dentro da cota", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{StoredBytes: 50 << 20, InFlight: 1, UploadsSince: 4})
+		assert.NoError(t, quota.CheckUpload(user, 50<<20))
+	})
+
+	t.Run("Espaço insuficiente", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{StoredBytes: 90 << 20})
+		assert.ErrorIs(t, quota.CheckUpload(user, 20<<20), usecase.ErrQuotaExceeded)
+	})
+
+	t.Run("Muitos vídeos em andamento", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{InFlight: 2})
+		assert.ErrorIs(t, quota.CheckUpload(user, 1), usecase.ErrRateLimited)
+	})
+
+	t.Run("Muitos envios na última hora", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{UploadsSince: 5})
+		assert.ErrorIs(t, quota.CheckUpload(user, 1), usecase.ErrRateLimited)
+	})
+
+	t.Run("CheckStorage ignora os limites de envio", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{InFlight: 2, UploadsSince: 5})
+		assert.NoError(t, quota.CheckStorage(user, 1<<20))
+	})
+
+	t.Run("Limite zero é ilimitado", func(t *testing.T) {
+		quota, _, _ := quotaWith(&entity.Plan{ID: "free"}, repository.VideoUsage{StoredBytes: 1 << 40, InFlight: 100})
+		assert.NoError(t, quota.CheckUpload(user, 1<<30))
+	})
+
+	t.Run("Plano inexistente usa o padrão", func(t *testing.T) {
+		quota, plans, _ := quotaWith(plan, repository.VideoUsage{InFlight: 2})
+		plans.On("FindByID", "enterprise").Return(nil, repository.ErrNotFound)
+
+		err := quota.CheckUpload(&entity.User{ID: "u1", PlanID: "enterprise"}, 1)
+		assert.ErrorIs(t, err, usecase.ErrRateLimited)
+	})
+
+	t.Run("QuotaService nil não limita", func(t *testing.T) {
+		var quota *usecase.QuotaService
+		assert.NoError(t, quota.CheckUpload(user, 1<<40))
+	})
+}
+
+func TestQuotaService_CheckBatch(t *testing.T) {
+	plan := &entity.Plan{ID: "free", MaxStorageBytes: 100 << 20, MaxVideosInFlight: 3, MaxUploadsPerHour: 5}
+	user := &entity.User{ID: "u1", PlanID: "free"}
+	sizes := []int64{10 << 20, 10 << 20, 10 << 20, 10 << 20}
+
+	t.Run("Limite de vídeos em andamento", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{InFlight: 1})
+		accepted, err := quota.CheckBatch(user, sizes)
+		assert.Equal(t, 2, accepted)
+		assert.ErrorIs(t, err, usecase.ErrRateLimited)
+	})
+
+	t.Run("Limite de envios por hora", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{UploadsSince: 4})
+		accepted, err := quota.CheckBatch(user, sizes)
+		assert.Equal(t, 1, accepted)
+		assert.ErrorContains(t, err, "envios por hora")
+	})
+
+	t.Run("Espaço somado do lote", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{StoredBytes: 75 << 20})
+		accepted, err := quota.CheckBatch(user, sizes)
+		assert.Equal(t, 2, accepted)
+		assert.ErrorIs(t, err, usecase.ErrQuotaExceeded)
+	})
+
+	t.Run("Nenhum cabe", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{InFlight: 3})
+		accepted, err := quota.CheckBatch(user, sizes)
+		assert.Equal(t, 0, accepted)
+		assert.ErrorIs(t, err, usecase.ErrRateLimited)
+	})
+
+	t.Run("Todos cabem", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{})
+		accepted, err := quota.CheckBatch(user, sizes[:3])
+		assert.Equal(t, 3, accepted)
+		assert.NoError(t, err)
+	})
+}
+
+func TestQuotaService_CheckInFlight(t *testing.T) {
+	plan := &entity.Plan{ID: "free", MaxStorageBytes: 100 << 20, MaxVideosInFlight: 2, MaxUploadsPerHour: 5}
+	user := &entity.User{ID: "u1", PlanID: "free"}
+
+	t.Run("Ignora envios e espaço", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{StoredBytes: 100 << 20, InFlight: 1, UploadsSince: 5})
+		assert.NoError(t, quota.CheckInFlight(user))
+	})
+
+	t.Run("Muitos vídeos em andamento", func(t *testing.T) {
+		quota, _, _ := quotaWith(plan, repository.VideoUsage{InFlight: 2})
+		assert.ErrorIs(t, quota.CheckInFlight(user), usecase.ErrRateLimited)
+	})
+}
+
+func TestQuotaService_UsageFor(t *testing.T) {
+	plan := &entity.Plan{ID: "pro", MaxStorageBytes: 1 << 30, MaxVideosInFlight: 10, MaxUploadsPerHour: 0}
+	quota, _, _ := quotaWith(plan, repository.VideoUsage{StoredBytes: 300, InFlight: 2, UploadsSince: 7})
+	userRepo := new(MockUserRepository)
+	quota.UserRepo = userRepo
+	userRepo.On("FindByID", "u1").Return(&entity.User{ID: "u1", PlanID: "pro"}, nil)
+
+	usage, err := quota.UsageFor("u1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "pro", usage.Plan)
+	assert.Equal(t, usecase.UsageMetric{Used: 300, Limit: 1 << 30}, usage.StorageBytes)
+	assert.Equal(t, usecase.UsageMetric{Used: 2, Limit: 10}, usage.VideosInFlight)
+	assert.Equal(t, usecase.UsageMetric{Used: 7, Limit: 0}, usage.UploadsLastHour)
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"time"
+)
+
+// Vídeos parados verificados por execução do RequeueStuck.
+const requeueBatchSize = 100
+
+// RequeuePolicy define quando um vídeo em PENDING ou PROCESSING é considerado
+// parado (o worker caiu ou a mensagem se perdeu): sem mudança de status nem
+// heartbeat do worker há StaleAfter. A partir daí ele é reenviado à fila até
+// MaxAttempts vezes antes de terminar em ERROR, e entre um reenvio e outro a
+// espera dobra, partindo de BaseBackoff até MaxBackoff. Os heartbeats só
+// evitam novos reenvios: a contagem não volta a zero quando um worker assume o
+// vídeo, só no Retry.
+type RequeuePolicy struct {
+	StaleAfter  time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func DefaultRequeuePolicy() RequeuePolicy {
+	return RequeuePolicy{
+		StaleAfter:  30 * time.Minute,
+		MaxAttempts: 3,
+		BaseBackoff: 5 * time.Minute,
+		MaxBackoff:  time.Hour,
+	}
+}
+
+func (p RequeuePolicy) backoff(attempts int) time.Duration {
+	delay := p.BaseBackoff
+	for i := 1; i < attempts && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxBackoff)
+}
+
+// RequeueStuck reenvia à fila os vídeos parados há mais de StaleAfter e marca
+// como ERROR os que já esgotaram os reenvios. Devolve quantos foram
+// reenviados e quantos falharam. Um vídeo que o worker atualizou durante a
+// verificação é ignorado.
+func (uc *VideoUseCase) RequeueStuck() (requeued, failed int, err error) {
+	now := time.Now()
+	videos, err := uc.Repo.FindStale(now.Add(-uc.Requeue.StaleAfter), now, requeueBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var errs []error
+	for i := range videos {
+		video := &videos[i]
+		exhausted := video.RequeueAttempts >= uc.Requeue.MaxAttempts
+
+		if exhausted {
+			err = uc.failStuck(video)
+		} else {
+			err = uc.requeue(video, now)
+		}
+
+		switch {
+		case errors.Is(err, repository.ErrConcurrentUpdate):
+			continue
+		case err != nil:
+			errs = append(errs, fmt.Errorf("vídeo %s: %w", video.ID, err))
+		case exhausted:
+			failed++
+		default:
+			requeued++
+		}
+	}
+
+	return requeued, failed, errors.Join(errs...)
+}
+
+// requeue grava a tentativa, o evento no histórico e a mensagem do outbox na
+// mesma transação. O vídeo continua no status atual: um worker lento ainda
+// pode concluir um vídeo em PROCESSING, e o worker que receber a nova mensagem
+// assume o vídeo enviando PROCESSING, que nesse caso vale como heartbeat.
+func (uc *VideoUseCase) requeue(video *entity.Video, now time.Time) error {
+	user, err := uc.UserRepo.FindByID(video.UserID)
+	if err != nil {
+		return err
+	}
+
+	video.RequeueAttempts++
+	next := now.Add(uc.Requeue.backoff(video.RequeueAttempts))
+	video.NextRequeueAt = &next
+
+	reason := fmt.Sprintf("Reenviado à fila (tentativa %d de %d)", video.RequeueAttempts, uc.Requeue.MaxAttempts)
+	event := entity.NewVideoStatusEvent(video.ID, video.Status, video.Status, entity.ActorSystem, reason)
+	return uc.Repo.UpdateStatusWithOutbox(video, event, entity.NewVideoOutboxMessage(entity.NewVideoMessage(video, user.Email)))
+}
+
+func (uc *VideoUseCase) failStuck(video *entity.Video) error {
+	since := video.UpdatedAt
+	if video.ActivityAt != nil {
+		since = *video.ActivityAt
+	}
+	video.ErrorMessage = fmt.Sprintf("Processamento não concluído: vídeo parado em %s desde %s após %d reenvios à fila",
+		video.Status, since.Format(time.RFC3339), video.RequeueAttempts)
+	video.NextRequeueAt = nil
+	return changeStatus(uc.Repo, video, entity.StatusError, entity.ActorSystem, video.ErrorMessage)
+}
@@ -0,0 +1,270 @@
+package usecase_test
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVideoUseCase_RequeueStuck(t *testing.T) {
+	stuck := func(status entity.VideoStatus, attempts int) entity.Video {
+		activity := time.Now().Add(-2 * time.Hour)
+		return entity.Video{ID: "v1", UserID: "u1", Status: status, RequeueAttempts: attempts, ActivityAt: &activity}
+	}
+	setup := func(videos ...entity.Video) (*usecase.VideoUseCase, *MockVideoRepository, *MockQueueService) {
+		repo, userRepo, queue := new(MockVideoRepository), new(MockUserRepository), new(MockQueueService)
+		uc := usecase.NewVideoUseCase(repo, userRepo, nil, queue)
+		repo.On("FindStale", mock.Anything, mock.Anything, 100).Return(videos, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{ID: "u1", Email: "e@e.com"}, nil)
+		return uc, repo, queue
+	}
+	requeueEvent := func(status entity.VideoStatus) interface{} {
+		return mock.MatchedBy(func(e *entity.VideoStatusEvent) bool {
+			return e.FromStatus == status && e.ToStatus == status && e.Actor == entity.ActorSystem
+		})
+	}
+	outboxFor := func(videoID string) interface{} {
+		return mock.MatchedBy(func(m *entity.OutboxMessage) bool { return m.VideoID == videoID })
+	}
+
+	t.Run("Sucesso: Vídeo PENDING é reenviado pelo outbox com backoff", func(t *testing.T) {
+		uc, repo, queue := setup(stuck(entity.StatusPending, 0))
+		before := time.Now()
+
+		repo.On("UpdateStatusWithOutbox", mock.MatchedBy(func(v *entity.Video) bool {
+			delay := v.NextRequeueAt.Sub(before)
+			return v.RequeueAttempts == 1 && delay >= 5*time.Minute && delay < 5*time.Minute+time.Second
+		}), requeueEvent(entity.StatusPending), outboxFor("v1")).Return(nil)
+
+		requeued, failed, err := uc.RequeueStuck()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, requeued)
+		assert.Equal(t, 0, failed)
+		repo.AssertExpectations(t)
+		queue.AssertNotCalled(t, "SendMessage", mock.Anything)
+	})
+
+	t.Run("Sucesso: Vídeo PROCESSING continua em PROCESSING", func(t *testing.T) {
+		uc, repo, _ := setup(stuck(entity.StatusProcessing, 1))
+		before := time.Now()
+
+		repo.On("UpdateStatusWithOutbox", mock.MatchedBy(func(v *entity.Video) bool {
+			delay := v.NextRequeueAt.Sub(before)
+			return v.Status == entity.StatusProcessing && v.RequeueAttempts == 2 && delay >= 10*time.Minute && delay < 10*time.Minute+time.Second
+		}), requeueEvent(entity.StatusProcessing), outboxFor("v1")).Return(nil)
+
+		requeued, _, err := uc.RequeueStuck()
+		assert.NoError(t, err)
+		assert.Equal(t, 1, requeued)
+	})
+
+	t.Run("Sucesso: Reenvios esgotados marcam ERROR", func(t *testing.T) {
+		uc, repo, _ := setup(stuck(entity.StatusProcessing, 3))
+
+		repo.On("UpdateStatus", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.Status == entity.StatusError && v.ErrorMessage != ""
+		}), mock.Anything).Return(nil)
+
+		requeued, failed, err := uc.RequeueStuck()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, requeued)
+		assert.Equal(t, 1, failed)
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Vídeo atualizado pelo worker é ignorado", func(t *testing.T) {
+		uc, repo, _ := setup(stuck(entity.StatusPending, 0))
+		repo.On("UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(repository.ErrConcurrentUpdate)
+
+		requeued, failed, err := uc.RequeueStuck()
+		assert.NoError(t, err)
+		assert.Equal(t, 0, requeued+failed)
+	})
+
+	t.Run("Erro: Falha ao gravar é reportada", func(t *testing.T) {
+		uc, repo, _ := setup(stuck(entity.StatusPending, 0))
+		repo.On("UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db fail"))
+
+		requeued, _, err := uc.RequeueStuck()
+		assert.ErrorContains(t, err, "db fail")
+		assert.Equal(t, 0, requeued)
+	})
+
+	t.Run("Sucesso: Backoff limitado ao máximo", func(t *testing.T) {
+		uc, repo, _ := setup(stuck(entity.StatusPending, 9))
+		uc.Requeue.MaxAttempts = 20
+		before := time.Now()
+
+		repo.On("UpdateStatusWithOutbox", mock.MatchedBy(func(v *entity.Video) bool {
+			delay := v.NextRequeueAt.Sub(before)
+			return delay >= time.Hour && delay < time.Hour+time.Second
+		}), mock.Anything, mock.Anything).Return(nil)
+
+		_, _, err := uc.RequeueStuck()
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+// staleRepo guarda um único vídeo e aplica em memória a condição de
+// FindStale. Cada gravação atualiza UpdatedAt, como no banco.
+type staleRepo struct {
+	MockVideoRepository
+	video entity.Video
+}
+
+func (r *staleRepo) FindStale(activeBefore, now time.Time, limit int) ([]entity.Video, error) {
+	v := r.video
+	inFlight := v.Status == entity.StatusPending || v.Status == entity.StatusProcessing
+	due := v.NextRequeueAt == nil || !v.NextRequeueAt.After(now)
+	if inFlight && v.ActivityAt.Before(activeBefore) && due {
+		return []entity.Video{v}, nil
+	}
+	return nil, nil
+}
+
+func (r *staleRepo) FindByID(id string) (*entity.Video, error) {
+	v := r.video
+	return &v, nil
+}
+
+func (r *staleRepo) Update(v *entity.Video) error {
+	r.video = *v
+	r.video.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *staleRepo) UpdateStatusWithOutbox(v *entity.Video, e *entity.VideoStatusEvent, m *entity.OutboxMessage) error {
+	return r.UpdateStatus(v, e)
+}
+
+func (r *staleRepo) UpdateStatus(v *entity.Video, e *entity.VideoStatusEvent) error {
+	r.video = *v
+	r.video.UpdatedAt = time.Now()
+	return nil
+}
+
+// advance simula a passagem do tempo recuando os horários gravados.
+func (r *staleRepo) advance(d time.Duration) {
+	activity := r.video.ActivityAt.Add(-d)
+	r.video.ActivityAt = &activity
+	if r.video.NextRequeueAt != nil {
+		next := r.video.NextRequeueAt.Add(-d)
+		r.video.NextRequeueAt = &next
+	}
+}
+
+func TestVideoUseCase_RequeueStuck_BackoffTiming(t *testing.T) {
+	activity := time.Now().Add(-29 * time.Minute)
+	repo := &staleRepo{video: entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusProcessing, ActivityAt: &activity}}
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", "u1").Return(&entity.User{ID: "u1", Email: "e@e.com"}, nil)
+	uc := usecase.NewVideoUseCase(repo, userRepo, nil, nil)
+
+	run := func() (int, int) {
+		requeued, failed, err := uc.RequeueStuck()
+		require.NoError(t, err)
+		return requeued, failed
+	}
+	steps := []struct {
+		advance  time.Duration
+		requeued int
+		failed   int
+	}{
+		{0, 0, 0},                // 29 minutos sem sinal: ainda não está parado
+		{2 * time.Minute, 1, 0},  // 31 minutos: primeiro reenvio
+		{4 * time.Minute, 0, 0},  // backoff de 5 minutos ainda não passou
+		{2 * time.Minute, 1, 0},  // segundo reenvio, sem esperar outro StaleAfter
+		{9 * time.Minute, 0, 0},  // backoff de 10 minutos
+		{2 * time.Minute, 1, 0},  // terceiro reenvio
+		{21 * time.Minute, 0, 1}, // backoff de 20 minutos e tentativas esgotadas
+	}
+	for i, step := range steps {
+		repo.advance(step.advance)
+		requeued, failed := run()
+		assert.Equal(t, step.requeued, requeued, "passo %d", i)
+		assert.Equal(t, step.failed, failed, "passo %d", i)
+	}
+	assert.Equal(t, entity.StatusError, repo.video.Status)
+}
+
+func TestVideoUseCase_RequeueStuck_PoisonVideoEndsInError(t *testing.T) {
+	activity := time.Now().Add(-31 * time.Minute)
+	repo := &staleRepo{video: entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusPending, ActivityAt: &activity}}
+	userRepo := new(MockUserRepository)
+	userRepo.On("FindByID", "u1").Return(&entity.User{ID: "u1", Email: "e@e.com"}, nil)
+	uc := usecase.NewVideoUseCase(repo, userRepo, nil, nil)
+
+	// Cada worker assume o vídeo e cai em seguida, sem concluir.
+	for attempt := 1; attempt <= uc.Requeue.MaxAttempts; attempt++ {
+		requeued, failed, err := uc.RequeueStuck()
+		require.NoError(t, err)
+		require.Equal(t, 1, requeued, "reenvio %d", attempt)
+		require.Equal(t, 0, failed)
+
+		_, err = uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusProcessing})
+		require.NoError(t, err)
+		assert.Equal(t, attempt, repo.video.RequeueAttempts, "assumir o vídeo não zera os reenvios")
+
+		repo.advance(2 * time.Hour)
+	}
+
+	requeued, failed, err := uc.RequeueStuck()
+	require.NoError(t, err)
+	assert.Equal(t, 0, requeued)
+	assert.Equal(t, 1, failed)
+	assert.Equal(t, entity.StatusError, repo.video.Status)
+}
+
+func TestVideoUseCase_UpdateProcessingStatus_Heartbeat(t *testing.T) {
+	next := time.Now().Add(10 * time.Minute)
+	requeued := func(status entity.VideoStatus) *entity.Video {
+		return &entity.Video{ID: "v1", Status: status, RequeueAttempts: 2, NextRequeueAt: &next}
+	}
+
+	t.Run("Sucesso: PROCESSING em PROCESSING renova a atividade sem zerar os reenvios", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(requeued(entity.StatusProcessing), nil)
+		repo.On("Update", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.Status == entity.StatusProcessing && v.RequeueAttempts == 2 && time.Since(*v.ActivityAt) < time.Second
+		})).Return(nil)
+
+		_, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusProcessing})
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Worker que assume o vídeo mantém os reenvios", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(requeued(entity.StatusPending), nil)
+		repo.On("UpdateStatus", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.Status == entity.StatusProcessing && v.RequeueAttempts == 2 && time.Since(*v.ActivityAt) < time.Second
+		}), mock.Anything).Return(nil)
+
+		_, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusProcessing})
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Sucesso: Worker original conclui um vídeo reenviado", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(requeued(entity.StatusProcessing), nil)
+		repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+		video, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusDone, OutputKey: "out.zip"})
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusDone, video.Status)
+		assert.Nil(t, video.NextRequeueAt)
+	})
+}
@@ -1,11 +1,20 @@
 package usecase_test
 
 import (
+	"bytes"
+	"encoding/binary"
 	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"io"
 	"mime/multipart"
+	"time"
 	"github.com/stretchr/testify/mock"
 )
 
+// verifiedAt confirma o e-mail dos usuários que enviam vídeos nos testes.
+var verifiedAt = time.Now()
+
 type MockUserRepository struct{ mock.Mock }
 func (m *MockUserRepository) Create(u *entity.User) error { return m.Called(u).Error(0) }
 func (m *MockUserRepository) FindByUsername(n string) (*entity.User, error) {
@@ -23,33 +32,275 @@ func (m *MockUserRepository) FindByID(id string) (*entity.User, error) {
 	if args.Get(0) == nil { return nil, args.Error(1) }
 	return args.Get(0).(*entity.User), args.Error(1)
 }
+func (m *MockUserRepository) Update(u *entity.User) error { return m.Called(u).Error(0) }
+func (m *MockUserRepository) MarkVerificationSent(id string, at, sentBefore time.Time) (bool, error) {
+	args := m.Called(id, at, sentBefore)
+	return args.Bool(0), args.Error(1)
+}
 
 type MockVideoRepository struct{ mock.Mock }
 func (m *MockVideoRepository) Create(v *entity.Video) error { return m.Called(v).Error(0) }
+func (m *MockVideoRepository) CreateWithOutbox(v *entity.Video, msg *entity.OutboxMessage) error {
+	return m.Called(v, msg).Error(0)
+}
 func (m *MockVideoRepository) FindByID(id string) (*entity.Video, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil { return nil, args.Error(1) }
 	return args.Get(0).(*entity.Video), args.Error(1)
 }
-func (m *MockVideoRepository) FindAllByUserID(id string) ([]entity.Video, error) {
-	args := m.Called(id)
+func (m *MockVideoRepository) FindPage(f repository.VideoFilter) ([]entity.Video, error) {
+	args := m.Called(f)
 	return args.Get(0).([]entity.Video), args.Error(1)
 }
+func (m *MockVideoRepository) Count(f repository.VideoFilter) (int64, error) {
+	args := m.Called(f)
+	return args.Get(0).(int64), args.Error(1)
+}
 func (m *MockVideoRepository) Update(v *entity.Video) error { return m.Called(v).Error(0) }
+func (m *MockVideoRepository) UpdateStatus(v *entity.Video, e *entity.VideoStatusEvent) error {
+	return m.Called(v, e).Error(0)
+}
+func (m *MockVideoRepository) UpdateStatusWithOutbox(v *entity.Video, e *entity.VideoStatusEvent, msg *entity.OutboxMessage) error {
+	return m.Called(v, e, msg).Error(0)
+}
+func (m *MockVideoRepository) FindStatusHistory(id string) ([]entity.VideoStatusEvent, error) {
+	args := m.Called(id)
+	return args.Get(0).([]entity.VideoStatusEvent), args.Error(1)
+}
+func (m *MockVideoRepository) Delete(v *entity.Video) error { return m.Called(v).Error(0) }
+func (m *MockVideoRepository) FindDeletedBefore(before time.Time, limit int) ([]entity.Video, error) {
+	args := m.Called(before, limit)
+	return args.Get(0).([]entity.Video), args.Error(1)
+}
+func (m *MockVideoRepository) FindStale(activeBefore, now time.Time, limit int) ([]entity.Video, error) {
+	args := m.Called(activeBefore, now, limit)
+	return args.Get(0).([]entity.Video), args.Error(1)
+}
+func (m *MockVideoRepository) FindAbandonedUploads(idleBefore time.Time, limit int) ([]entity.Video, error) {
+	args := m.Called(idleBefore, limit)
+	return args.Get(0).([]entity.Video), args.Error(1)
+}
+func (m *MockVideoRepository) Purge(id string) error { return m.Called(id).Error(0) }
+func (m *MockVideoRepository) Usage(id string, since time.Time) (*repository.VideoUsage, error) {
+	args := m.Called(id, since)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*repository.VideoUsage), args.Error(1)
+}
 
-type MockTokenGenerator struct{ mock.Mock }
-func (m *MockTokenGenerator) GenerateToken(id string) (string, error) {
+type MockOutboxRepository struct{ mock.Mock }
+func (m *MockOutboxRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]entity.OutboxMessage, error) {
+	args := m.Called(now, lease, limit)
+	return args.Get(0).([]entity.OutboxMessage), args.Error(1)
+}
+func (m *MockOutboxRepository) Update(msg *entity.OutboxMessage) error { return m.Called(msg).Error(0) }
+func (m *MockOutboxRepository) DeleteSentBefore(before time.Time) (int64, error) {
+	args := m.Called(before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockPresetRepository struct{ mock.Mock }
+func (m *MockPresetRepository) Create(p *entity.Preset) error { return m.Called(p).Error(0) }
+func (m *MockPresetRepository) Update(p *entity.Preset) error { return m.Called(p).Error(0) }
+func (m *MockPresetRepository) FindByID(id string) (*entity.Preset, error) {
 	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Preset), args.Error(1)
+}
+func (m *MockPresetRepository) FindByName(userID, name string) (*entity.Preset, error) {
+	args := m.Called(userID, name)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Preset), args.Error(1)
+}
+func (m *MockPresetRepository) FindDefault(userID string) (*entity.Preset, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Preset), args.Error(1)
+}
+func (m *MockPresetRepository) FindAllByUserID(userID string) ([]entity.Preset, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]entity.Preset), args.Error(1)
+}
+func (m *MockPresetRepository) Delete(p *entity.Preset) error { return m.Called(p).Error(0) }
+
+type MockBatchRepository struct{ mock.Mock }
+func (m *MockBatchRepository) Create(b *entity.Batch) error { return m.Called(b).Error(0) }
+func (m *MockBatchRepository) FindByID(id string) (*entity.Batch, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Batch), args.Error(1)
+}
+func (m *MockBatchRepository) FindRecentByUserID(userID string, limit int) ([]entity.Batch, error) {
+	args := m.Called(userID, limit)
+	return args.Get(0).([]entity.Batch), args.Error(1)
+}
+
+type MockShareRepository struct{ mock.Mock }
+func (m *MockShareRepository) Create(s *entity.Share) error { return m.Called(s).Error(0) }
+func (m *MockShareRepository) FindByID(id string) (*entity.Share, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Share), args.Error(1)
+}
+func (m *MockShareRepository) FindByTokenHash(hash string) (*entity.Share, error) {
+	args := m.Called(hash)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Share), args.Error(1)
+}
+func (m *MockShareRepository) FindAllByVideoID(videoID string) ([]entity.Share, error) {
+	args := m.Called(videoID)
+	return args.Get(0).([]entity.Share), args.Error(1)
+}
+func (m *MockShareRepository) Revoke(id string, at time.Time) error { return m.Called(id, at).Error(0) }
+func (m *MockShareRepository) RegisterDownload(id string, at time.Time) (bool, error) {
+	args := m.Called(id, at)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockShareRepository) RegisterPasswordFailure(id string, maxFailures int, lockUntil time.Time) error {
+	return m.Called(id, maxFailures, lockUntil).Error(0)
+}
+
+type MockPlanRepository struct{ mock.Mock }
+func (m *MockPlanRepository) FindByID(id string) (*entity.Plan, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Plan), args.Error(1)
+}
+func (m *MockPlanRepository) EnsureExists(p *entity.Plan) error { return m.Called(p).Error(0) }
+
+type MockTokenGenerator struct{ mock.Mock }
+func (m *MockTokenGenerator) GenerateToken(id, sid string) (string, error) {
+	args := m.Called(id, sid)
+	return args.String(0), args.Error(1)
+}
+
+type MockVerificationTokens struct{ mock.Mock }
+func (m *MockVerificationTokens) GenerateVerificationToken(id, email string) (string, error) {
+	args := m.Called(id, email)
 	return args.String(0), args.Error(1)
 }
+func (m *MockVerificationTokens) ValidateVerificationToken(token string) (string, string, error) {
+	args := m.Called(token)
+	return args.String(0), args.String(1), args.Error(2)
+}
+
+type MockMailer struct{ mock.Mock }
+func (m *MockMailer) Send(msg usecase.EmailMessage) error { return m.Called(msg).Error(0) }
 
 type MockStorageService struct{ mock.Mock }
 func (m *MockStorageService) UploadFile(f multipart.File, k string) error { return m.Called(f, k).Error(0) }
-func (m *MockStorageService) GeneratePresignedURL(k string) (string, error) {
-	args := m.Called(k)
+func (m *MockStorageService) GeneratePresignedURL(k, fileName string) (string, error) {
+	args := m.Called(k, fileName)
 	return args.String(0), args.Error(1)
 }
 func (m *MockStorageService) GetBucketName() string { return m.Called().String(0) }
+func (m *MockStorageService) DeleteObject(k string) error { return m.Called(k).Error(0) }
+func (m *MockStorageService) ReadObjectHeader(k string, n int64) ([]byte, error) {
+	args := m.Called(k, n)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).([]byte), args.Error(1)
+}
+func (m *MockStorageService) OpenObject(k string) (io.ReaderAt, int64, error) {
+	args := m.Called(k)
+	if args.Get(0) == nil { return nil, args.Get(1).(int64), args.Error(2) }
+	return args.Get(0).(io.ReaderAt), args.Get(1).(int64), args.Error(2)
+}
+func (m *MockStorageService) CreateMultipartUpload(k string) (string, error) {
+	args := m.Called(k)
+	return args.String(0), args.Error(1)
+}
+func (m *MockStorageService) UploadPart(k, uploadID string, n int32, b io.Reader, size int64) (string, error) {
+	args := m.Called(k, uploadID, n, b, size)
+	return args.String(0), args.Error(1)
+}
+func (m *MockStorageService) CompleteMultipartUpload(k, uploadID string, p []entity.UploadPart) error {
+	return m.Called(k, uploadID, p).Error(0)
+}
+func (m *MockStorageService) AbortMultipartUpload(k, uploadID string) error { return m.Called(k, uploadID).Error(0) }
+func (m *MockStorageService) GeneratePresignedUploadURL(k, contentType string, size int64) (string, error) {
+	args := m.Called(k, contentType, size)
+	return args.String(0), args.Error(1)
+}
+func (m *MockStorageService) CopyObject(src, dst string) error { return m.Called(src, dst).Error(0) }
+func (m *MockStorageService) ObjectExists(k string) (bool, error) {
+	args := m.Called(k)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockStorageService) ObjectSize(k string) (int64, error) {
+	args := m.Called(k)
+	return args.Get(0).(int64), args.Error(1)
+}
+func (m *MockStorageService) ReadObject(k string) (io.ReadCloser, error) {
+	args := m.Called(k)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+// mp4Header é o início de um MP4 real (caixa ftyp), suficiente para o sniffing.
+var mp4Header = []byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00isomiso2avc1mp41")
+
+// sampleMP4 monta um MP4 mínimo (ftyp + moov) com uma trilha de vídeo H.264.
+func sampleMP4(seconds uint32, width, height uint16) []byte {
+	box := func(boxType string, payload ...[]byte) []byte {
+		body := bytes.Join(payload, nil)
+		return append(append(binary.BigEndian.AppendUint32(nil, uint32(8+len(body))), boxType...), body...)
+	}
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:], uint32(width)<<16)
+	binary.BigEndian.PutUint32(tkhd[80:], uint32(height)<<16)
+	hdlr := append(make([]byte, 8), "vide"...)
+	stsd := box("stsd", []byte{0, 0, 0, 0, 0, 0, 0, 1}, box("avc1", make([]byte, 36)))
+	mvhd := binary.BigEndian.AppendUint32(make([]byte, 12), 1)
+	mvhd = binary.BigEndian.AppendUint32(mvhd, seconds)
+
+	moov := box("moov",
+		box("mvhd", mvhd),
+		box("trak", box("tkhd", tkhd), box("mdia", box("hdlr", hdlr), box("minf", box("stbl", stsd)))),
+	)
+	return append(append([]byte{}, mp4Header...), moov...)
+}
+
+type fakeFile struct{ *bytes.Reader }
+func (fakeFile) Close() error { return nil }
+
+func videoFile(content []byte) multipart.File { return fakeFile{bytes.NewReader(content)} }
 
 type MockQueueService struct{ mock.Mock }
-func (m *MockQueueService) SendMessage(id, email string) error { return m.Called(id, email).Error(0) }
\ No newline at end of file
+func (m *MockQueueService) SendMessage(msg entity.VideoMessage) error { return m.Called(msg).Error(0) }
+
+// videoMessage casa a mensagem da fila pelo vídeo e pelo e-mail.
+func videoMessage(videoID, email string) interface{} {
+	return mock.MatchedBy(func(msg entity.VideoMessage) bool {
+		return msg.SchemaVersion == entity.VideoMessageSchemaVersion && msg.VideoID == videoID && msg.Email == email
+	})
+}
+
+type MockUploadPartRepository struct{ mock.Mock }
+func (m *MockUploadPartRepository) Save(p *entity.UploadPart) error { return m.Called(p).Error(0) }
+func (m *MockUploadPartRepository) FindAllByVideoID(id string) ([]entity.UploadPart, error) {
+	args := m.Called(id)
+	return args.Get(0).([]entity.UploadPart), args.Error(1)
+}
+func (m *MockUploadPartRepository) DeleteAllByVideoID(id string) error { return m.Called(id).Error(0) }
+
+type MockSessionRepository struct{ mock.Mock }
+func (m *MockSessionRepository) Create(s *entity.Session) error { return m.Called(s).Error(0) }
+func (m *MockSessionRepository) FindByID(id string) (*entity.Session, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Session), args.Error(1)
+}
+func (m *MockSessionRepository) FindByTokenHash(h string) (*entity.Session, error) {
+	args := m.Called(h)
+	if args.Get(0) == nil { return nil, args.Error(1) }
+	return args.Get(0).(*entity.Session), args.Error(1)
+}
+func (m *MockSessionRepository) FindActiveByUserID(id string) ([]entity.Session, error) {
+	args := m.Called(id)
+	return args.Get(0).([]entity.Session), args.Error(1)
+}
+func (m *MockSessionRepository) Rotate(s *entity.Session, currentHash string) (bool, error) {
+	args := m.Called(s, currentHash)
+	return args.Bool(0), args.Error(1)
+}
+func (m *MockSessionRepository) Revoke(id string, at time.Time) error { return m.Called(id, at).Error(0) }
+func (m *MockSessionRepository) RevokeAllByUserID(id string) error { return m.Called(id).Error(0) }
\ No newline at end of file
@@ -0,0 +1,158 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"time"
+)
+
+var (
+	ErrInvalidShare     = errors.New("compartilhamento inválido")
+	ErrShareUnavailable = errors.New("link de compartilhamento indisponível")
+	ErrSharePassword    = errors.New("senha do link incorreta")
+	ErrShareLocked      = errors.New("muitas senhas incorretas: tente de novo mais tarde")
+)
+
+// Limites dos links de compartilhamento. A senha segue o limite do bcrypt.
+// Após MaxSharePasswordFailures senhas incorretas seguidas, o link recusa
+// novas tentativas por SharePasswordLockout.
+const (
+	DefaultShareTTL          = 7 * 24 * time.Hour
+	MaxShareTTL              = 30 * 24 * time.Hour
+	MaxShareDownloads        = 1000
+	MaxSharePasswordLen      = 72
+	MaxSharePasswordFailures = 5
+	SharePasswordLockout     = 15 * time.Minute
+)
+
+// ShareInput são as opções de um novo link. ExpiresIn zero usa
+// DefaultShareTTL e MaxDownloads zero não limita os downloads.
+type ShareInput struct {
+	ExpiresIn    time.Duration
+	MaxDownloads int
+	Password     string
+}
+
+type ShareUseCase struct {
+	Repo   repository.ShareRepository
+	Videos *VideoUseCase
+}
+
+func NewShareUseCase(repo repository.ShareRepository, videos *VideoUseCase) *ShareUseCase {
+	return &ShareUseCase{Repo: repo, Videos: videos}
+}
+
+// Create devolve o link e o token, que não fica gravado e só pode ser
+// mostrado agora.
+func (uc *ShareUseCase) Create(userID, videoID string, input ShareInput) (*entity.Share, string, error) {
+	video, err := uc.Videos.findOwned(userID, videoID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !video.DownloadAvailable() {
+		return nil, "", ErrVideoNotReady
+	}
+
+	if input.ExpiresIn == 0 {
+		input.ExpiresIn = DefaultShareTTL
+	}
+	switch {
+	case input.ExpiresIn < time.Minute || input.ExpiresIn > MaxShareTTL:
+		return nil, "", fmt.Errorf("%w: validade deve ficar entre 1 minuto e %d dias", ErrInvalidShare, int(MaxShareTTL.Hours()/24))
+	case input.MaxDownloads < 0 || input.MaxDownloads > MaxShareDownloads:
+		return nil, "", fmt.Errorf("%w: max_downloads deve ficar entre 0 (sem limite) e %d", ErrInvalidShare, MaxShareDownloads)
+	case len(input.Password) > MaxSharePasswordLen:
+		return nil, "", fmt.Errorf("%w: senha com mais de %d bytes", ErrInvalidShare, MaxSharePasswordLen)
+	}
+
+	share, token, err := entity.NewShare(video.ID, userID, time.Now().Add(input.ExpiresIn), input.MaxDownloads, input.Password)
+	if err != nil {
+		return nil, "", err
+	}
+	if err := uc.Repo.Create(share); err != nil {
+		return nil, "", err
+	}
+	return share, token, nil
+}
+
+func (uc *ShareUseCase) List(userID, videoID string) ([]entity.Share, error) {
+	if _, err := uc.Videos.findOwned(userID, videoID); err != nil {
+		return nil, err
+	}
+	return uc.Repo.FindAllByVideoID(videoID)
+}
+
+func (uc *ShareUseCase) Revoke(userID, videoID, shareID string) (*entity.Share, error) {
+	if _, err := uc.Videos.findOwned(userID, videoID); err != nil {
+		return nil, err
+	}
+
+	share, err := uc.Repo.FindByID(shareID)
+	if err != nil {
+		return nil, err
+	}
+	if share.VideoID != videoID {
+		return nil, repository.ErrNotFound
+	}
+
+	// Revogar de novo não é erro. O link é relido para devolver a data da
+	// primeira revogação e os downloads contados até ela.
+	if err := uc.Repo.Revoke(share.ID, time.Now()); err != nil {
+		return nil, err
+	}
+	return uc.Repo.FindByID(share.ID)
+}
+
+// Resolve troca o token por um link pré-assinado da saída do vídeo e conta o
+// download. Cada chamada conta um download, mesmo que o link pré-assinado não
+// chegue a ser usado.
+func (uc *ShareUseCase) Resolve(token, password string) (string, error) {
+	share, err := uc.Repo.FindByTokenHash(entity.HashToken(token))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	if !share.IsActive(now) {
+		return "", ErrShareUnavailable
+	}
+	// O bloqueio vem antes do bcrypt: um link bloqueado não custa CPU.
+	if share.IsLocked(now) {
+		return "", ErrShareLocked
+	}
+	if !share.ValidatePassword(password) {
+		if err := uc.Repo.RegisterPasswordFailure(share.ID, MaxSharePasswordFailures, now.Add(SharePasswordLockout)); err != nil {
+			return "", err
+		}
+		return "", ErrSharePassword
+	}
+
+	// Um vídeo removido (ou reprocessado) deixa de ser encontrado ou de ter
+	// download, e o link para de funcionar junto.
+	video, err := uc.Videos.Repo.FindByID(share.VideoID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", ErrShareUnavailable
+	}
+	if err != nil {
+		return "", err
+	}
+	if !video.DownloadAvailable() {
+		return "", ErrShareUnavailable
+	}
+
+	url, err := uc.Videos.Storage.GeneratePresignedURL(video.OutputKey, outputFileName(*video))
+	if err != nil {
+		return "", err
+	}
+
+	registered, err := uc.Repo.RegisterDownload(share.ID, now)
+	if err != nil {
+		return "", err
+	}
+	if !registered {
+		return "", ErrShareUnavailable
+	}
+	return url, nil
+}
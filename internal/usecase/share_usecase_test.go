@@ -0,0 +1,191 @@
+package usecase_test
+
+import (
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShareUseCase_Create(t *testing.T) {
+	setup := func(video *entity.Video) (*usecase.ShareUseCase, *MockShareRepository) {
+		shares, repo := new(MockShareRepository), new(MockVideoRepository)
+		uc := usecase.NewShareUseCase(shares, usecase.NewVideoUseCase(repo, nil, nil, nil))
+		repo.On("FindByID", "v1").Return(video, nil)
+		return uc, shares
+	}
+	done := &entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusDone, OutputKey: "outputs/v1.zip"}
+
+	t.Run("Sucesso: Link com senha guarda só os hashes", func(t *testing.T) {
+		uc, shares := setup(done)
+		shares.On("Create", mock.Anything).Return(nil)
+		before := time.Now()
+
+		share, token, err := uc.Create("u1", "v1", usecase.ShareInput{MaxDownloads: 5, Password: "segredo"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, entity.HashToken(token), share.TokenHash)
+		assert.True(t, share.HasPassword())
+		assert.NotEqual(t, "segredo", share.PasswordHash)
+		assert.WithinDuration(t, before.Add(usecase.DefaultShareTTL), share.ExpiresAt, time.Second)
+	})
+
+	t.Run("Erro: Vídeo ainda não processado", func(t *testing.T) {
+		uc, shares := setup(&entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusProcessing})
+
+		_, _, err := uc.Create("u1", "v1", usecase.ShareInput{})
+		assert.ErrorIs(t, err, usecase.ErrVideoNotReady)
+		shares.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Erro: Validade acima do máximo", func(t *testing.T) {
+		uc, _ := setup(done)
+
+		_, _, err := uc.Create("u1", "v1", usecase.ShareInput{ExpiresIn: usecase.MaxShareTTL + time.Hour})
+		assert.ErrorIs(t, err, usecase.ErrInvalidShare)
+	})
+
+	t.Run("Erro: Vídeo de outro usuário", func(t *testing.T) {
+		uc, _ := setup(done)
+
+		_, _, err := uc.Create("u2", "v1", usecase.ShareInput{})
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+}
+
+func TestShareUseCase_Resolve(t *testing.T) {
+	done := &entity.Video{ID: "v1", UserID: "u1", FileName: "aula.mp4", Status: entity.StatusDone, OutputKey: "outputs/v1.zip"}
+	newShare := func(t *testing.T, maxDownloads int, password string) (*entity.Share, string) {
+		share, token, err := entity.NewShare("v1", "u1", time.Now().Add(time.Hour), maxDownloads, password)
+		require.NoError(t, err)
+		return share, token
+	}
+	setup := func(share *entity.Share) (*usecase.ShareUseCase, *MockShareRepository, *MockStorageService) {
+		shares, repo, storage := new(MockShareRepository), new(MockVideoRepository), new(MockStorageService)
+		uc := usecase.NewShareUseCase(shares, usecase.NewVideoUseCase(repo, nil, storage, nil))
+		shares.On("FindByTokenHash", share.TokenHash).Return(share, nil)
+		repo.On("FindByID", "v1").Return(done, nil)
+		storage.On("GeneratePresignedURL", "outputs/v1.zip", "aula.zip").Return("http://s3/v1.zip", nil)
+		return uc, shares, storage
+	}
+
+	t.Run("Sucesso: Gera o link e conta o download", func(t *testing.T) {
+		share, token := newShare(t, 0, "")
+		uc, shares, _ := setup(share)
+		shares.On("RegisterDownload", share.ID, mock.Anything).Return(true, nil)
+
+		url, err := uc.Resolve(token, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://s3/v1.zip", url)
+		shares.AssertExpectations(t)
+	})
+
+	t.Run("Erro: Senha incorreta conta a falha e não conta download", func(t *testing.T) {
+		share, token := newShare(t, 0, "segredo")
+		uc, shares, _ := setup(share)
+		shares.On("RegisterPasswordFailure", share.ID, usecase.MaxSharePasswordFailures, mock.MatchedBy(func(until time.Time) bool {
+			return time.Until(until) > usecase.SharePasswordLockout-time.Second && time.Until(until) <= usecase.SharePasswordLockout
+		})).Return(nil)
+
+		_, err := uc.Resolve(token, "errada")
+		assert.ErrorIs(t, err, usecase.ErrSharePassword)
+		shares.AssertExpectations(t)
+		shares.AssertNotCalled(t, "RegisterDownload", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Link bloqueado recusa até a senha certa", func(t *testing.T) {
+		share, token := newShare(t, 0, "segredo")
+		lockedUntil := time.Now().Add(time.Minute)
+		share.LockedUntil = &lockedUntil
+		uc, shares, storage := setup(share)
+
+		_, err := uc.Resolve(token, "segredo")
+		assert.ErrorIs(t, err, usecase.ErrShareLocked)
+		shares.AssertNotCalled(t, "RegisterPasswordFailure", mock.Anything, mock.Anything, mock.Anything)
+		storage.AssertNotCalled(t, "GeneratePresignedURL", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Bloqueio vencido aceita a senha", func(t *testing.T) {
+		share, token := newShare(t, 0, "segredo")
+		lockedUntil := time.Now().Add(-time.Second)
+		share.LockedUntil = &lockedUntil
+		uc, shares, _ := setup(share)
+		shares.On("RegisterDownload", share.ID, mock.Anything).Return(true, nil)
+
+		url, err := uc.Resolve(token, "segredo")
+		assert.NoError(t, err)
+		assert.Equal(t, "http://s3/v1.zip", url)
+	})
+
+	t.Run("Erro: Downloads esgotados", func(t *testing.T) {
+		share, token := newShare(t, 2, "")
+		share.DownloadCount = 2
+		uc, _, _ := setup(share)
+
+		_, err := uc.Resolve(token, "")
+		assert.ErrorIs(t, err, usecase.ErrShareUnavailable)
+	})
+
+	t.Run("Erro: Limite atingido por outro download simultâneo", func(t *testing.T) {
+		share, token := newShare(t, 1, "")
+		uc, shares, _ := setup(share)
+		shares.On("RegisterDownload", share.ID, mock.Anything).Return(false, nil)
+
+		_, err := uc.Resolve(token, "")
+		assert.ErrorIs(t, err, usecase.ErrShareUnavailable)
+	})
+
+	t.Run("Erro: Link revogado", func(t *testing.T) {
+		share, token := newShare(t, 0, "")
+		share.Revoke()
+		uc, _, storage := setup(share)
+
+		_, err := uc.Resolve(token, "")
+		assert.ErrorIs(t, err, usecase.ErrShareUnavailable)
+		storage.AssertNotCalled(t, "GeneratePresignedURL", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Token desconhecido", func(t *testing.T) {
+		shares := new(MockShareRepository)
+		uc := usecase.NewShareUseCase(shares, usecase.NewVideoUseCase(nil, nil, nil, nil))
+		shares.On("FindByTokenHash", entity.HashToken("x")).Return(nil, repository.ErrNotFound)
+
+		_, err := uc.Resolve("x", "")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestShareUseCase_Revoke(t *testing.T) {
+	t.Run("Erro: Link de outro vídeo", func(t *testing.T) {
+		shares, repo := new(MockShareRepository), new(MockVideoRepository)
+		uc := usecase.NewShareUseCase(shares, usecase.NewVideoUseCase(repo, nil, nil, nil))
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1"}, nil)
+		shares.On("FindByID", "s1").Return(&entity.Share{ID: "s1", VideoID: "v2"}, nil)
+
+		_, err := uc.Revoke("u1", "v1", "s1")
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+		shares.AssertNotCalled(t, "Revoke", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Revoga só a data e devolve o link relido", func(t *testing.T) {
+		shares, repo := new(MockShareRepository), new(MockVideoRepository)
+		uc := usecase.NewShareUseCase(shares, usecase.NewVideoUseCase(repo, nil, nil, nil))
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1"}, nil)
+		revokedAt := time.Now()
+		// Um download contado entre as duas leituras aparece no link devolvido.
+		shares.On("FindByID", "s1").Return(&entity.Share{ID: "s1", VideoID: "v1", ExpiresAt: time.Now().Add(time.Hour), DownloadCount: 1}, nil).Once()
+		shares.On("FindByID", "s1").Return(&entity.Share{ID: "s1", VideoID: "v1", ExpiresAt: time.Now().Add(time.Hour), DownloadCount: 2, RevokedAt: &revokedAt}, nil).Once()
+		shares.On("Revoke", "s1", mock.Anything).Return(nil)
+
+		share, err := uc.Revoke("u1", "v1", "s1")
+		assert.NoError(t, err)
+		assert.False(t, share.IsActive(time.Now()))
+		assert.Equal(t, 2, share.DownloadCount)
+		shares.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"time"
+)
+
+// Uploads abandonados expirados por execução do ExpireAbandoned.
+const expireBatchSize = 100
+
+// ExpireAbandoned encerra os uploads em UPLOADING sem atividade há idle: o
+// cliente desistiu e o vídeo não pode continuar contando como em andamento na
+// cota. O multipart é abortado no S3 e o vídeo termina em ERROR.
+func (uc *UploadUseCase) ExpireAbandoned(idle time.Duration) (int, error) {
+	videos, err := uc.Repo.FindAbandonedUploads(time.Now().Add(-idle), expireBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for i := range videos {
+		if err := uc.expire(&videos[i]); err != nil {
+			errs = append(errs, fmt.Errorf("vídeo %s: %w", videos[i].ID, err))
+			continue
+		}
+		expired++
+	}
+
+	return expired, errors.Join(errs...)
+}
+
+// expire grava o ERROR antes de abortar: se o cliente concluir o upload ao
+// mesmo tempo, a atualização versionada falha e o multipart fica intacto. O
+// UploadID continua no vídeo, e o purge tenta o abort de novo caso este falhe.
+func (uc *UploadUseCase) expire(video *entity.Video) error {
+	video.ErrorMessage = "Upload expirado por inatividade"
+	if err := changeStatus(uc.Repo, video, entity.StatusError, entity.ActorSystem, video.ErrorMessage); err != nil {
+		return err
+	}
+
+	if video.UploadID != "" {
+		if err := uc.Storage.AbortMultipartUpload(video.InputKey, video.UploadID); err != nil {
+			return err
+		}
+	}
+	uc.deleteParts(video)
+	return nil
+}
@@ -0,0 +1,77 @@
+package usecase_test
+
+import (
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/repository"
+	"hackaton-service-api/internal/usecase"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUploadUseCase_ExpireAbandoned(t *testing.T) {
+	setup := func(videos ...entity.Video) (*usecase.UploadUseCase, *MockVideoRepository, *MockUploadPartRepository, *MockStorageService) {
+		repo, partRepo, storage := new(MockVideoRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, nil, partRepo, storage)
+		repo.On("FindAbandonedUploads", mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= 24*time.Hour && time.Since(before) < 24*time.Hour+time.Second
+		}), 100).Return(videos, nil)
+		partRepo.On("DeleteAllByVideoID", mock.Anything).Return(nil)
+		return uc, repo, partRepo, storage
+	}
+	expiredEvent := mock.MatchedBy(func(e *entity.VideoStatusEvent) bool {
+		return e.FromStatus == entity.StatusUploading && e.ToStatus == entity.StatusError && e.Actor == entity.ActorSystem
+	})
+
+	t.Run("Sucesso: Multipart abortado e vídeo em ERROR", func(t *testing.T) {
+		uc, repo, partRepo, storage := setup(*openUpload())
+		storage.On("AbortMultipartUpload", "uploads/1_v.mp4", "up-1").Return(nil)
+		repo.On("UpdateStatus", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.Status == entity.StatusError && v.ErrorMessage == "Upload expirado por inatividade"
+		}), expiredEvent).Return(nil)
+
+		expired, err := uc.ExpireAbandoned(24 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, expired)
+		repo.AssertExpectations(t)
+		partRepo.AssertCalled(t, "DeleteAllByVideoID", "v1")
+	})
+
+	t.Run("Sucesso: Upload direto não tem multipart para abortar", func(t *testing.T) {
+		uc, repo, _, storage := setup(entity.Video{ID: "v1", UserID: "u1", InputKey: "uploads/1_v.mp4", Status: entity.StatusUploading})
+		repo.On("UpdateStatus", mock.Anything, expiredEvent).Return(nil)
+
+		expired, err := uc.ExpireAbandoned(24 * time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, expired)
+		storage.AssertNotCalled(t, "AbortMultipartUpload", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Falha no abort mantém o UploadID para o purge", func(t *testing.T) {
+		uc, repo, partRepo, storage := setup(*openUpload())
+		repo.On("UpdateStatus", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.Status == entity.StatusError && v.UploadID == "up-1"
+		}), expiredEvent).Return(nil)
+		storage.On("AbortMultipartUpload", "uploads/1_v.mp4", "up-1").Return(errors.New("s3 error"))
+
+		expired, err := uc.ExpireAbandoned(24 * time.Hour)
+		assert.EqualError(t, err, "vídeo v1: s3 error")
+		assert.Equal(t, 0, expired)
+		repo.AssertExpectations(t)
+		partRepo.AssertNotCalled(t, "DeleteAllByVideoID", mock.Anything)
+	})
+
+	t.Run("Erro: Upload concluído ao mesmo tempo não é abortado", func(t *testing.T) {
+		uc, repo, partRepo, storage := setup(*openUpload())
+		repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(repository.ErrConcurrentUpdate)
+
+		expired, err := uc.ExpireAbandoned(24 * time.Hour)
+		assert.ErrorIs(t, err, repository.ErrConcurrentUpdate)
+		assert.Equal(t, 0, expired)
+		storage.AssertNotCalled(t, "AbortMultipartUpload", mock.Anything, mock.Anything)
+		partRepo.AssertNotCalled(t, "DeleteAllByVideoID", mock.Anything)
+	})
+}
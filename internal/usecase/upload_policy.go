@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/media"
+	"io"
+	"slices"
+	"time"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("formato não suportado")
+	ErrFileTooLarge      = errors.New("arquivo maior que o permitido")
+	ErrVideoLimits       = errors.New("vídeo excede os limites permitidos")
+)
+
+const DefaultMaxUploadSize int64 = 2 << 30
+
+// UploadPolicy define os contêineres aceitos e o tamanho máximo dos vídeos.
+// O limite pode ser sobrescrito por usuário em User.MaxUploadSize. Duração e
+// resolução máximas valem para todos; zero significa sem limite. Com
+// RequireVerifiedEmail, só usuários com e-mail confirmado enviam vídeos.
+type UploadPolicy struct {
+	AllowedFormats       []media.Format
+	MaxSize              int64
+	MaxDuration          time.Duration
+	MaxWidth             int
+	MaxHeight            int
+	RequireVerifiedEmail bool
+}
+
+func DefaultUploadPolicy() UploadPolicy {
+	return UploadPolicy{
+		AllowedFormats:       []media.Format{media.FormatMP4, media.FormatMKV, media.FormatAVI},
+		MaxSize:              DefaultMaxUploadSize,
+		RequireVerifiedEmail: true,
+	}
+}
+
+func (p UploadPolicy) CheckEmail(user *entity.User) error {
+	if p.RequireVerifiedEmail && !user.IsEmailVerified() {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+func (p UploadPolicy) MaxSizeFor(user *entity.User) int64 {
+	if user.MaxUploadSize > 0 {
+		return user.MaxUploadSize
+	}
+	return p.MaxSize
+}
+
+func (p UploadPolicy) CheckSize(user *entity.User, size int64) error {
+	if limit := p.MaxSizeFor(user); size > limit {
+		return fmt.Errorf("%w: limite de %d MB", ErrFileTooLarge, limit>>20)
+	}
+	return nil
+}
+
+// CheckExtension é a validação possível antes de o conteúdo chegar; o
+// conteúdo é conferido depois com CheckContent.
+func (p UploadPolicy) CheckExtension(fileName string) (media.Format, error) {
+	return p.allow(media.FromExtension(fileName))
+}
+
+// CheckContent identifica o contêiner pelos primeiros bytes, ignorando a
+// extensão do nome do arquivo.
+func (p UploadPolicy) CheckContent(header []byte) (media.Format, error) {
+	return p.allow(media.Sniff(header))
+}
+
+func (p UploadPolicy) allow(format media.Format) (media.Format, error) {
+	if format == "" {
+		return "", ErrUnsupportedFormat
+	}
+	if !slices.Contains(p.AllowedFormats, format) {
+		return "", fmt.Errorf("%w: %s não é aceito", ErrUnsupportedFormat, format)
+	}
+	return format, nil
+}
+
+// CheckMetadata compara a resolução sem considerar a orientação: um limite de
+// 1920x1080 aceita também vídeos verticais 1080x1920.
+func (p UploadPolicy) CheckMetadata(meta *media.Metadata) error {
+	if p.MaxDuration > 0 && meta.Duration > p.MaxDuration {
+		return fmt.Errorf("%w: duração máxima de %s", ErrVideoLimits, p.MaxDuration)
+	}
+
+	if p.MaxWidth > 0 && p.MaxHeight > 0 {
+		long, short := max(meta.Width, meta.Height), min(meta.Width, meta.Height)
+		if long > max(p.MaxWidth, p.MaxHeight) || short > min(p.MaxWidth, p.MaxHeight) {
+			return fmt.Errorf("%w: resolução máxima de %dx%d", ErrVideoLimits, p.MaxWidth, p.MaxHeight)
+		}
+	}
+	return nil
+}
+
+// inspect extrai os metadados do contêiner para o vídeo. Os metadados são
+// informativos: um arquivo que não pôde ser lido segue para o worker, e só
+// os limites da política rejeitam o upload.
+func (p UploadPolicy) inspect(video *entity.Video, r io.ReaderAt, size int64) error {
+	meta, err := media.Probe(r, size)
+	if err != nil {
+		return nil
+	}
+
+	video.DurationSeconds = meta.Duration.Seconds()
+	video.Width = meta.Width
+	video.Height = meta.Height
+	video.VideoCodec = meta.VideoCodec
+	video.Bitrate = meta.Bitrate
+
+	return p.CheckMetadata(meta)
+}
+
+// readHeader lê os bytes usados no sniffing sem consumir o arquivo.
+func readHeader(r io.ReaderAt) ([]byte, error) {
+	header := make([]byte, media.SniffLength)
+	n, err := r.ReadAt(header, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	return header[:n], nil
+}
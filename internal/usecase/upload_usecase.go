@@ -0,0 +1,436 @@
+package usecase
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/media"
+	"hackaton-service-api/internal/repository"
+	"io"
+)
+
+// Limites do S3 para uploads multipart. Toda parte, exceto a de maior número,
+// precisa ter ao menos MinPartSize bytes.
+const (
+	MinPartNumber = 1
+	MaxPartNumber = 10000
+	MinPartSize   = 5 << 20
+)
+
+type UploadStorageService interface {
+	CreateMultipartUpload(key string) (string, error)
+	// UploadPart recebe o corpo em streaming com o tamanho já conhecido; um
+	// corpo de tamanho diferente de size é rejeitado.
+	UploadPart(key, uploadID string, partNumber int32, body io.Reader, size int64) (string, error)
+	CompleteMultipartUpload(key, uploadID string, parts []entity.UploadPart) error
+	AbortMultipartUpload(key, uploadID string) error
+	GeneratePresignedUploadURL(key, contentType string, size int64) (string, error)
+	ObjectExists(key string) (bool, error)
+	CopyObject(srcKey, dstKey string) error
+	DeleteObject(key string) error
+	ReadObjectHeader(key string, n int64) ([]byte, error)
+	OpenObject(key string) (io.ReaderAt, int64, error)
+	GetBucketName() string
+}
+
+// UploadUseCase orquestra os uploads que não passam inteiros pela API: em
+// partes (multipart) ou direto para o S3 via URL pré-assinada. Em ambos o
+// vídeo fica em UPLOADING e só é enfileirado após o complete.
+type UploadUseCase struct {
+	Repo     repository.VideoRepository
+	UserRepo repository.UserRepository
+	PartRepo repository.UploadPartRepository
+	Storage  UploadStorageService
+	Policy   UploadPolicy
+	Quota    *QuotaService
+}
+
+func NewUploadUseCase(repo repository.VideoRepository, userRepo repository.UserRepository, partRepo repository.UploadPartRepository, storage UploadStorageService) *UploadUseCase {
+	return &UploadUseCase{
+		Repo:     repo,
+		UserRepo: userRepo,
+		PartRepo: partRepo,
+		Storage:  storage,
+		Policy:   DefaultUploadPolicy(),
+	}
+}
+
+func (uc *UploadUseCase) InitiateUpload(userID, fileName string) (*entity.Video, error) {
+	format, err := uc.Policy.CheckExtension(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("usuário não encontrado")
+	}
+
+	if err := uc.Policy.CheckEmail(user); err != nil {
+		return nil, err
+	}
+
+	// O tamanho final só é conhecido parte a parte; o espaço é conferido de
+	// novo em cada UploadPart.
+	if err := uc.Quota.CheckUpload(user, 0); err != nil {
+		return nil, err
+	}
+
+	video := entity.NewVideo(userID, fileName, "uploads/"+uniqueFileName(fileName))
+	video.InputBucket = uc.Storage.GetBucketName()
+	video.Status = entity.StatusUploading
+	video.ContentType = format.ContentType()
+
+	uploadID, err := uc.Storage.CreateMultipartUpload(video.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	video.UploadID = uploadID
+
+	if err := uc.Repo.Create(video); err != nil {
+		uc.Storage.AbortMultipartUpload(video.InputKey, uploadID)
+		return nil, err
+	}
+
+	return video, nil
+}
+
+func (uc *UploadUseCase) UploadPart(userID, videoID string, partNumber int32, body io.Reader, size int64) (*entity.UploadPart, error) {
+	if partNumber < MinPartNumber || partNumber > MaxPartNumber {
+		return nil, fmt.Errorf("número da parte inválido")
+	}
+
+	video, err := uc.findMultipartUpload(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.checkUploadedSize(video, partNumber, size); err != nil {
+		return nil, err
+	}
+
+	// A primeira parte traz o cabeçalho do contêiner: o conteúdo é conferido
+	// antes de seguir, e não só a extensão informada no início do upload.
+	if partNumber == MinPartNumber {
+		header := make([]byte, media.SniffLength)
+		n, err := io.ReadFull(body, header)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		if _, err := uc.Policy.CheckContent(header[:n]); err != nil {
+			return nil, err
+		}
+		body = io.MultiReader(bytes.NewReader(header[:n]), body)
+	}
+
+	etag, err := uc.Storage.UploadPart(video.InputKey, video.UploadID, partNumber, body, size)
+	if err != nil {
+		return nil, err
+	}
+
+	part := entity.NewUploadPart(video.ID, partNumber, etag, size)
+	if err := uc.PartRepo.Save(part); err != nil {
+		return nil, err
+	}
+
+	return part, nil
+}
+
+// ListParts devolve as partes já confirmadas, para o cliente saber de onde retomar.
+func (uc *UploadUseCase) ListParts(userID, videoID string) ([]entity.UploadPart, error) {
+	video, err := uc.findMultipartUpload(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+	return uc.PartRepo.FindAllByVideoID(video.ID)
+}
+
+func (uc *UploadUseCase) CompleteUpload(userID, videoID string) (*entity.Video, error) {
+	video, err := uc.findMultipartUpload(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts, err := uc.PartRepo.FindAllByVideoID(video.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("nenhuma parte enviada")
+	}
+	// O S3 recusaria o complete; a parte pequena ainda pode ser reenviada.
+	if err := checkPartSizes(parts); err != nil {
+		return nil, err
+	}
+
+	user, err := uc.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("usuário não encontrado")
+	}
+
+	// Uma tentativa anterior pode ter montado o arquivo e falhado ao gravar o
+	// vídeo: o multipart já não existe, mas o objeto sim, e a conclusão segue
+	// a partir dele.
+	if err := uc.Storage.CompleteMultipartUpload(video.InputKey, video.UploadID, parts); err != nil {
+		exists, existsErr := uc.Storage.ObjectExists(video.InputKey)
+		if existsErr != nil || !exists {
+			return nil, err
+		}
+	}
+
+	video.UploadID = ""
+	video.InputSize = 0
+	for _, part := range parts {
+		video.InputSize += part.Size
+	}
+
+	// O arquivo montado não pode mais ser reenviado: fora dos limites, o
+	// vídeo termina em ERROR em vez de ficar preso em UPLOADING.
+	if err := uc.inspectObject(video); err != nil {
+		video.ErrorMessage = err.Error()
+		if statusErr := changeStatus(uc.Repo, video, entity.StatusError, entity.ActorSystem, err.Error()); statusErr != nil {
+			return nil, statusErr
+		}
+		uc.deleteParts(video)
+		return nil, err
+	}
+
+	if err := uc.enqueue(video, user); err != nil {
+		return nil, err
+	}
+	uc.deleteParts(video)
+
+	return video, nil
+}
+
+// deleteParts só é chamado depois que o vídeo saiu de UPLOADING: até lá as
+// partes são o que permite repetir o CompleteUpload. Uma falha aqui deixa
+// registros sem uso, removidos junto com o vídeo no Purge.
+func (uc *UploadUseCase) deleteParts(video *entity.Video) {
+	uc.PartRepo.DeleteAllByVideoID(video.ID)
+}
+
+// RequestDirectUpload cria o vídeo e devolve uma URL de PUT pré-assinada, com
+// Content-Type e Content-Length fixados na assinatura, para o cliente enviar
+// o arquivo direto ao S3 sem passar pela API.
+func (uc *UploadUseCase) RequestDirectUpload(userID, fileName, contentType string, size int64) (*entity.Video, string, error) {
+	if size <= 0 {
+		return nil, "", fmt.Errorf("tamanho do arquivo inválido")
+	}
+
+	if _, err := uc.Policy.CheckExtension(fileName); err != nil {
+		return nil, "", err
+	}
+
+	user, err := uc.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, "", fmt.Errorf("usuário não encontrado")
+	}
+
+	if err := uc.Policy.CheckEmail(user); err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.Policy.CheckSize(user, size); err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.Quota.CheckUpload(user, size); err != nil {
+		return nil, "", err
+	}
+
+	video := entity.NewVideo(userID, fileName, "uploads/"+uniqueFileName(fileName))
+	video.InputBucket = uc.Storage.GetBucketName()
+	video.Status = entity.StatusUploading
+	video.InputSize = size
+	video.ContentType = contentType
+
+	url, err := uc.Storage.GeneratePresignedUploadURL(video.InputKey, contentType, size)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := uc.Repo.Create(video); err != nil {
+		return nil, "", err
+	}
+
+	return video, url, nil
+}
+
+// ConfirmDirectUpload confere via HeadObject que o arquivo chegou ao S3 e o
+// copia para uma chave nova antes de enfileirar o vídeo. A URL de PUT segue
+// válida depois da confirmação; sem a cópia, o cliente poderia trocar o
+// arquivo já conferido antes de o worker lê-lo.
+func (uc *UploadUseCase) ConfirmDirectUpload(userID, videoID string) (*entity.Video, error) {
+	video, err := uc.findOpenUpload(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.UploadID != "" {
+		return nil, fmt.Errorf("upload em partes deve ser finalizado pelo complete do multipart")
+	}
+
+	exists, err := uc.Storage.ObjectExists(video.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("arquivo ainda não foi enviado")
+	}
+
+	uploadedKey := video.InputKey
+	video.InputKey = "uploads/" + uniqueFileName(video.FileName)
+	if err := uc.Storage.CopyObject(uploadedKey, video.InputKey); err != nil {
+		return nil, err
+	}
+
+	// Se a cópia for recusada, o vídeo continua em UPLOADING com a chave
+	// original, e o cliente pode enviar outro arquivo pela mesma URL.
+	if err := uc.checkAndEnqueue(userID, video); err != nil {
+		uc.Storage.DeleteObject(video.InputKey)
+		return nil, err
+	}
+	uc.Storage.DeleteObject(uploadedKey)
+
+	return video, nil
+}
+
+// checkAndEnqueue confere a cópia do upload direto, que o cliente não tem
+// como alterar, e enfileira o vídeo.
+func (uc *UploadUseCase) checkAndEnqueue(userID string, video *entity.Video) error {
+	// O S3 só garante o tamanho e o Content-Type assinados; o conteúdo é
+	// conferido lendo o início do objeto.
+	header, err := uc.Storage.ReadObjectHeader(video.InputKey, media.SniffLength)
+	if err != nil {
+		return err
+	}
+	format, err := uc.Policy.CheckContent(header)
+	if err != nil {
+		return err
+	}
+	video.ContentType = format.ContentType()
+
+	if err := uc.inspectObject(video); err != nil {
+		return err
+	}
+
+	user, err := uc.UserRepo.FindByID(userID)
+	if err != nil {
+		return fmt.Errorf("usuário não encontrado")
+	}
+
+	return uc.enqueue(video, user)
+}
+
+// enqueue grava a transição para PENDING e a mensagem do outbox na mesma
+// transação; o OutboxRelay faz a publicação na fila.
+func (uc *UploadUseCase) enqueue(video *entity.Video, user *entity.User) error {
+	event, err := video.TransitionTo(entity.StatusPending, entity.UserActor(user.ID), "Upload concluído")
+	if err != nil {
+		return err
+	}
+	return uc.Repo.UpdateStatusWithOutbox(video, event, entity.NewVideoOutboxMessage(entity.NewVideoMessage(video, user.Email)))
+}
+
+func (uc *UploadUseCase) AbortUpload(userID, videoID string) error {
+	video, err := uc.findMultipartUpload(userID, videoID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.Storage.AbortMultipartUpload(video.InputKey, video.UploadID); err != nil {
+		return err
+	}
+	uc.PartRepo.DeleteAllByVideoID(video.ID)
+
+	video.ErrorMessage = "Upload cancelado"
+	video.UploadID = ""
+	return changeStatus(uc.Repo, video, entity.StatusError, entity.UserActor(userID), video.ErrorMessage)
+}
+
+// inspectObject lê os metadados do arquivo já no S3 com GETs por intervalo.
+// Falha de leitura não impede o processamento.
+func (uc *UploadUseCase) inspectObject(video *entity.Video) error {
+	r, size, err := uc.Storage.OpenObject(video.InputKey)
+	if err != nil {
+		return nil
+	}
+	return uc.Policy.inspect(video, r, size)
+}
+
+// checkUploadedSize soma as partes já recebidas (substituindo a de mesmo
+// número) para barrar o upload assim que ultrapassar o limite do usuário ou
+// deixar uma parte intermediária abaixo do mínimo do S3.
+func (uc *UploadUseCase) checkUploadedSize(video *entity.Video, partNumber int32, size int64) error {
+	user, err := uc.UserRepo.FindByID(video.UserID)
+	if err != nil {
+		return fmt.Errorf("usuário não encontrado")
+	}
+
+	parts, err := uc.PartRepo.FindAllByVideoID(video.ID)
+	if err != nil {
+		return err
+	}
+
+	total := size
+	merged := []entity.UploadPart{{PartNumber: partNumber, Size: size}}
+	for _, part := range parts {
+		if part.PartNumber != partNumber {
+			total += part.Size
+			merged = append(merged, part)
+		}
+	}
+	if err := checkPartSizes(merged); err != nil {
+		return err
+	}
+	if err := uc.Policy.CheckSize(user, total); err != nil {
+		return err
+	}
+	return uc.Quota.CheckStorage(user, total)
+}
+
+// checkPartSizes só deixa a parte de maior número ficar abaixo de MinPartSize.
+func checkPartSizes(parts []entity.UploadPart) error {
+	var last int32
+	for _, part := range parts {
+		last = max(last, part.PartNumber)
+	}
+	for _, part := range parts {
+		if part.PartNumber != last && part.Size < MinPartSize {
+			return fmt.Errorf("parte %d tem %d bytes: só a última parte pode ter menos de %d", part.PartNumber, part.Size, MinPartSize)
+		}
+	}
+	return nil
+}
+
+func (uc *UploadUseCase) findOpenUpload(userID, videoID string) (*entity.Video, error) {
+	video, err := uc.Repo.FindByID(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.UserID != userID {
+		return nil, ErrAccessDenied
+	}
+
+	if video.Status != entity.StatusUploading {
+		return nil, fmt.Errorf("upload não está em andamento")
+	}
+
+	return video, nil
+}
+
+func (uc *UploadUseCase) findMultipartUpload(userID, videoID string) (*entity.Video, error) {
+	video, err := uc.findOpenUpload(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.UploadID == "" {
+		return nil, fmt.Errorf("upload não está em andamento")
+	}
+
+	return video, nil
+}
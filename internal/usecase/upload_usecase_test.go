@@ -0,0 +1,447 @@
+package usecase_test
+
+import (
+	"bytes"
+	"errors"
+	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/usecase"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func openUpload() *entity.Video {
+	return &entity.Video{
+		ID:       "v1",
+		UserID:   "u1",
+		InputKey: "uploads/1_v.mp4",
+		UploadID: "up-1",
+		Status:   entity.StatusUploading,
+	}
+}
+
+func TestUploadUseCase_InitiateUpload(t *testing.T) {
+	t.Run("Erro: Formato de arquivo não suportado", func(t *testing.T) {
+		uc := usecase.NewUploadUseCase(nil, nil, nil, nil)
+		video, err := uc.InitiateUpload("u1", "documento.pdf")
+
+		assert.Nil(t, video)
+		assert.EqualError(t, err, "formato não suportado")
+	})
+
+	t.Run("Erro: E-mail não confirmado", func(t *testing.T) {
+		userRepo, storage := new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(nil, userRepo, nil, storage)
+
+		userRepo.On("FindByID", "u1").Return(&entity.User{}, nil)
+
+		_, err := uc.InitiateUpload("u1", "v.mp4")
+		assert.ErrorIs(t, err, usecase.ErrEmailNotVerified)
+		storage.AssertNotCalled(t, "CreateMultipartUpload", mock.Anything)
+	})
+
+	t.Run("Erro: Falha ao criar registro aborta o multipart", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, nil, storage)
+
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		storage.On("GetBucketName").Return("bucket")
+		storage.On("CreateMultipartUpload", mock.Anything).Return("up-1", nil)
+		repo.On("Create", mock.Anything).Return(errors.New("db error"))
+		storage.On("AbortMultipartUpload", mock.Anything, "up-1").Return(nil)
+
+		video, err := uc.InitiateUpload("u1", "v.mp4")
+		assert.Nil(t, video)
+		assert.EqualError(t, err, "db error")
+		storage.AssertCalled(t, "AbortMultipartUpload", mock.Anything, "up-1")
+	})
+
+	t.Run("Sucesso: Vídeo criado em UPLOADING", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, nil, storage)
+
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		storage.On("GetBucketName").Return("bucket")
+		storage.On("CreateMultipartUpload", mock.Anything).Return("up-1", nil)
+		repo.On("Create", mock.Anything).Return(nil)
+
+		video, err := uc.InitiateUpload("u1", "v.mp4")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusUploading, video.Status)
+		assert.Equal(t, "up-1", video.UploadID)
+		assert.Equal(t, "bucket", video.InputBucket)
+	})
+}
+
+func TestUploadUseCase_UploadPart(t *testing.T) {
+	t.Run("Erro: Número da parte inválido", func(t *testing.T) {
+		uc := usecase.NewUploadUseCase(nil, nil, nil, nil)
+		_, err := uc.UploadPart("u1", "v1", 0, nil, 0)
+		assert.EqualError(t, err, "número da parte inválido")
+	})
+
+	t.Run("Erro: Acesso negado", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewUploadUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+
+		_, err := uc.UploadPart("hacker", "v1", 1, nil, 0)
+		assert.EqualError(t, err, "acesso negado")
+	})
+
+	t.Run("Erro: Upload já finalizado", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewUploadUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{UserID: "u1", Status: entity.StatusPending}, nil)
+
+		_, err := uc.UploadPart("u1", "v1", 1, nil, 0)
+		assert.EqualError(t, err, "upload não está em andamento")
+	})
+
+	t.Run("Erro: Limite do arquivo excedido", func(t *testing.T) {
+		repo, userRepo, partRepo := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, nil)
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, MaxUploadSize: 10 << 20}, nil)
+		partRepo.On("FindAllByVideoID", "v1").Return([]entity.UploadPart{{PartNumber: 1, Size: 6 << 20}}, nil)
+
+		_, err := uc.UploadPart("u1", "v1", 2, strings.NewReader("dados"), 6<<20)
+		assert.ErrorIs(t, err, usecase.ErrFileTooLarge)
+	})
+
+	t.Run("Erro: Parte intermediária menor que o mínimo do S3", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		partRepo.On("FindAllByVideoID", "v1").Return([]entity.UploadPart{{PartNumber: 3, Size: 100}}, nil)
+
+		_, err := uc.UploadPart("u1", "v1", 2, strings.NewReader("dados"), 5)
+		assert.EqualError(t, err, "parte 2 tem 5 bytes: só a última parte pode ter menos de 5242880")
+		storage.AssertNotCalled(t, "UploadPart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Primeira parte não é um vídeo", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		partRepo.On("FindAllByVideoID", "v1").Return([]entity.UploadPart{}, nil)
+
+		_, err := uc.UploadPart("u1", "v1", 1, strings.NewReader("%PDF-1.7 renomeado"), 18)
+		assert.ErrorIs(t, err, usecase.ErrUnsupportedFormat)
+		storage.AssertNotCalled(t, "UploadPart", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Primeira parte enviada inteira após o sniffing", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		content := append(append([]byte{}, mp4Header...), strings.Repeat("x", 100)...)
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		partRepo.On("FindAllByVideoID", "v1").Return([]entity.UploadPart{}, nil)
+		storage.On("UploadPart", "uploads/1_v.mp4", "up-1", int32(1), mock.MatchedBy(func(r io.Reader) bool {
+			sent, _ := io.ReadAll(r)
+			return bytes.Equal(sent, content)
+		}), int64(len(content))).Return("\"etag-1\"", nil)
+		partRepo.On("Save", mock.Anything).Return(nil)
+
+		_, err := uc.UploadPart("u1", "v1", 1, bytes.NewReader(content), int64(len(content)))
+		assert.NoError(t, err)
+	})
+
+	t.Run("Sucesso: Parte registrada", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		body := strings.NewReader("dados")
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		partRepo.On("FindAllByVideoID", "v1").Return([]entity.UploadPart{}, nil)
+		storage.On("UploadPart", "uploads/1_v.mp4", "up-1", int32(2), body, int64(5)).Return("\"etag-2\"", nil)
+		partRepo.On("Save", mock.Anything).Return(nil)
+
+		part, err := uc.UploadPart("u1", "v1", 2, body, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(2), part.PartNumber)
+		assert.Equal(t, "\"etag-2\"", part.ETag)
+	})
+}
+
+func TestUploadUseCase_CompleteUpload(t *testing.T) {
+	t.Run("Erro: Nenhuma parte enviada", func(t *testing.T) {
+		repo, partRepo := new(MockVideoRepository), new(MockUploadPartRepository)
+		uc := usecase.NewUploadUseCase(repo, nil, partRepo, nil)
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		partRepo.On("FindAllByVideoID", "v1").Return([]entity.UploadPart{}, nil)
+
+		_, err := uc.CompleteUpload("u1", "v1")
+		assert.EqualError(t, err, "nenhuma parte enviada")
+	})
+
+	t.Run("Erro: Parte intermediária menor que o mínimo não chega ao S3", func(t *testing.T) {
+		repo, partRepo, storage := new(MockVideoRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, nil, partRepo, storage)
+		parts := []entity.UploadPart{{VideoID: "v1", PartNumber: 1, ETag: "e1", Size: 100}, {VideoID: "v1", PartNumber: 2, ETag: "e2", Size: 100}}
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		partRepo.On("FindAllByVideoID", "v1").Return(parts, nil)
+
+		_, err := uc.CompleteUpload("u1", "v1")
+		assert.EqualError(t, err, "parte 1 tem 100 bytes: só a última parte pode ter menos de 5242880")
+		storage.AssertNotCalled(t, "CompleteMultipartUpload", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Falha no S3 não enfileira", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		parts := []entity.UploadPart{{VideoID: "v1", PartNumber: 1, ETag: "e1"}}
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		partRepo.On("FindAllByVideoID", "v1").Return(parts, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("CompleteMultipartUpload", "uploads/1_v.mp4", "up-1", parts).Return(errors.New("s3 error"))
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(false, nil)
+
+		_, err := uc.CompleteUpload("u1", "v1")
+		assert.EqualError(t, err, "s3 error")
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Falha ao gravar o outbox devolve o erro", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		parts := []entity.UploadPart{{VideoID: "v1", PartNumber: 1, ETag: "e1"}}
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		partRepo.On("FindAllByVideoID", "v1").Return(parts, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("CompleteMultipartUpload", mock.Anything, mock.Anything, parts).Return(nil)
+		storage.On("OpenObject", "uploads/1_v.mp4").Return(nil, int64(0), errors.New("s3 error"))
+		repo.On("UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("db fail"))
+
+		_, err := uc.CompleteUpload("u1", "v1")
+		assert.EqualError(t, err, "db fail")
+		partRepo.AssertNotCalled(t, "DeleteAllByVideoID", mock.Anything)
+	})
+
+	t.Run("Sucesso: Nova tentativa depois de o arquivo já ter sido montado", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		parts := []entity.UploadPart{{VideoID: "v1", PartNumber: 1, ETag: "e1", Size: 100}}
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		partRepo.On("FindAllByVideoID", "v1").Return(parts, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("CompleteMultipartUpload", mock.Anything, mock.Anything, parts).Return(errors.New("NoSuchUpload"))
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(true, nil)
+		storage.On("OpenObject", "uploads/1_v.mp4").Return(nil, int64(0), errors.New("s3 error"))
+		repo.On("UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		partRepo.On("DeleteAllByVideoID", "v1").Return(nil)
+
+		video, err := uc.CompleteUpload("u1", "v1")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusPending, video.Status)
+		assert.Equal(t, int64(100), video.InputSize)
+		partRepo.AssertCalled(t, "DeleteAllByVideoID", "v1")
+	})
+
+	t.Run("Sucesso: Vídeo enfileirado após a última parte", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		parts := []entity.UploadPart{{VideoID: "v1", PartNumber: 1, ETag: "e1", Size: usecase.MinPartSize}, {VideoID: "v1", PartNumber: 2, ETag: "e2", Size: 100}}
+
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+		partRepo.On("FindAllByVideoID", "v1").Return(parts, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("CompleteMultipartUpload", "uploads/1_v.mp4", "up-1", parts).Return(nil)
+		partRepo.On("DeleteAllByVideoID", "v1").Return(nil)
+		content := sampleMP4(60, 1280, 720)
+		storage.On("OpenObject", "uploads/1_v.mp4").Return(bytes.NewReader(content), int64(len(content)), nil)
+		repo.On("UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.MatchedBy(func(msg *entity.OutboxMessage) bool {
+			message, err := msg.VideoMessage()
+			return err == nil && message.VideoID == "v1" && message.Email == "e@e.com"
+		})).Return(nil)
+
+		video, err := uc.CompleteUpload("u1", "v1")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusPending, video.Status)
+		assert.Empty(t, video.UploadID)
+		assert.Equal(t, 60.0, video.DurationSeconds)
+		assert.Equal(t, 1280, video.Width)
+		assert.Equal(t, 720, video.Height)
+		assert.Equal(t, "h264", video.VideoCodec)
+	})
+
+	t.Run("Erro: Vídeo acima da duração máxima termina em ERROR", func(t *testing.T) {
+		repo, userRepo, partRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockUploadPartRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, partRepo, storage)
+		uc.Policy.MaxDuration = 10 * time.Minute
+		video := openUpload()
+		parts := []entity.UploadPart{{VideoID: "v1", PartNumber: 1, ETag: "e1"}}
+
+		repo.On("FindByID", "v1").Return(video, nil)
+		partRepo.On("FindAllByVideoID", "v1").Return(parts, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("CompleteMultipartUpload", mock.Anything, mock.Anything, parts).Return(nil)
+		partRepo.On("DeleteAllByVideoID", "v1").Return(nil)
+		content := sampleMP4(3600, 1280, 720)
+		storage.On("OpenObject", "uploads/1_v.mp4").Return(bytes.NewReader(content), int64(len(content)), nil)
+		repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := uc.CompleteUpload("u1", "v1")
+		assert.ErrorIs(t, err, usecase.ErrVideoLimits)
+		assert.Equal(t, entity.StatusError, video.Status)
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestUploadUseCase_AbortUpload(t *testing.T) {
+	repo, partRepo, storage := new(MockVideoRepository), new(MockUploadPartRepository), new(MockStorageService)
+	uc := usecase.NewUploadUseCase(repo, nil, partRepo, storage)
+	video := openUpload()
+
+	repo.On("FindByID", "v1").Return(video, nil)
+	storage.On("AbortMultipartUpload", "uploads/1_v.mp4", "up-1").Return(nil)
+	partRepo.On("DeleteAllByVideoID", "v1").Return(nil)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+	err := uc.AbortUpload("u1", "v1")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.StatusError, video.Status)
+	assert.Equal(t, "Upload cancelado", video.ErrorMessage)
+}
+
+func TestUploadUseCase_RequestDirectUpload(t *testing.T) {
+	t.Run("Erro: Tamanho inválido", func(t *testing.T) {
+		uc := usecase.NewUploadUseCase(nil, nil, nil, nil)
+		_, _, err := uc.RequestDirectUpload("u1", "v.mp4", "video/mp4", 0)
+		assert.EqualError(t, err, "tamanho do arquivo inválido")
+	})
+
+	t.Run("Sucesso: Retorna URL pré-assinada", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, nil, storage)
+
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		storage.On("GetBucketName").Return("bucket")
+		storage.On("GeneratePresignedUploadURL", mock.Anything, "video/mp4", int64(1024)).Return("http://s3/put", nil)
+		repo.On("Create", mock.Anything).Return(nil)
+
+		video, url, err := uc.RequestDirectUpload("u1", "v.mp4", "video/mp4", 1024)
+		assert.NoError(t, err)
+		assert.Equal(t, "http://s3/put", url)
+		assert.Equal(t, entity.StatusUploading, video.Status)
+		assert.Empty(t, video.UploadID)
+	})
+}
+
+func TestUploadUseCase_ConfirmDirectUpload(t *testing.T) {
+	directUpload := func() *entity.Video {
+		return &entity.Video{ID: "v1", UserID: "u1", FileName: "v.mp4", InputKey: "uploads/1_v.mp4", Status: entity.StatusUploading}
+	}
+	// A cópia recebe um nome novo, que o cliente não conhece.
+	copyKey := mock.MatchedBy(func(key string) bool {
+		return key != "uploads/1_v.mp4" && strings.HasPrefix(key, "uploads/") && strings.HasSuffix(key, "_v.mp4")
+	})
+	setup := func(userRepo *MockUserRepository) (*usecase.UploadUseCase, *MockVideoRepository, *MockStorageService, *entity.Video) {
+		repo, storage := new(MockVideoRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, userRepo, nil, storage)
+		video := directUpload()
+		repo.On("FindByID", "v1").Return(video, nil)
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(true, nil)
+		storage.On("CopyObject", "uploads/1_v.mp4", copyKey).Return(nil)
+		storage.On("DeleteObject", mock.Anything).Return(nil)
+		return uc, repo, storage, video
+	}
+
+	t.Run("Erro: Arquivo ainda não está no S3", func(t *testing.T) {
+		repo, storage := new(MockVideoRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, nil, nil, storage)
+
+		repo.On("FindByID", "v1").Return(directUpload(), nil)
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(false, nil)
+
+		_, err := uc.ConfirmDirectUpload("u1", "v1")
+		assert.EqualError(t, err, "arquivo ainda não foi enviado")
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+		storage.AssertNotCalled(t, "CopyObject", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Falha na cópia não enfileira", func(t *testing.T) {
+		repo, storage := new(MockVideoRepository), new(MockStorageService)
+		uc := usecase.NewUploadUseCase(repo, nil, nil, storage)
+
+		repo.On("FindByID", "v1").Return(directUpload(), nil)
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(true, nil)
+		storage.On("CopyObject", "uploads/1_v.mp4", copyKey).Return(errors.New("s3 error"))
+
+		_, err := uc.ConfirmDirectUpload("u1", "v1")
+		assert.EqualError(t, err, "s3 error")
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+		storage.AssertNotCalled(t, "DeleteObject", mock.Anything)
+	})
+
+	t.Run("Erro: Conteúdo enviado não é um vídeo", func(t *testing.T) {
+		uc, repo, storage, _ := setup(nil)
+		storage.On("ReadObjectHeader", copyKey, mock.Anything).Return([]byte("%PDF-1.7"), nil)
+
+		_, err := uc.ConfirmDirectUpload("u1", "v1")
+		assert.ErrorIs(t, err, usecase.ErrUnsupportedFormat)
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+		storage.AssertCalled(t, "DeleteObject", copyKey)
+		storage.AssertNotCalled(t, "DeleteObject", "uploads/1_v.mp4")
+	})
+
+	t.Run("Erro: Upload multipart não pode ser confirmado aqui", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewUploadUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(openUpload(), nil)
+
+		_, err := uc.ConfirmDirectUpload("u1", "v1")
+		assert.Error(t, err)
+	})
+
+	t.Run("Sucesso: Vídeo enfileirado a partir da cópia", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		uc, repo, storage, _ := setup(userRepo)
+		storage.On("ReadObjectHeader", copyKey, mock.Anything).Return(mp4Header, nil)
+		storage.On("OpenObject", copyKey).Return(nil, int64(0), errors.New("s3 error"))
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		repo.On("UpdateStatusWithOutbox", mock.MatchedBy(func(v *entity.Video) bool {
+			return v.InputKey != "uploads/1_v.mp4"
+		}), mock.Anything, mock.MatchedBy(func(m *entity.OutboxMessage) bool {
+			return !strings.Contains(m.Payload, "uploads/1_v.mp4")
+		})).Return(nil)
+
+		video, err := uc.ConfirmDirectUpload("u1", "v1")
+		assert.NoError(t, err, "metadados indisponíveis não impedem o processamento")
+		assert.Equal(t, entity.StatusPending, video.Status)
+		repo.AssertExpectations(t)
+		storage.AssertCalled(t, "DeleteObject", "uploads/1_v.mp4")
+		storage.AssertNotCalled(t, "DeleteObject", video.InputKey)
+	})
+
+	t.Run("Erro: Resolução acima do limite mantém o upload aberto", func(t *testing.T) {
+		uc, repo, storage, video := setup(nil)
+		uc.Policy.MaxWidth, uc.Policy.MaxHeight = 1920, 1080
+		storage.On("ReadObjectHeader", copyKey, mock.Anything).Return(mp4Header, nil)
+		content := sampleMP4(60, 3840, 2160)
+		storage.On("OpenObject", copyKey).Return(bytes.NewReader(content), int64(len(content)), nil)
+
+		_, err := uc.ConfirmDirectUpload("u1", "v1")
+		assert.ErrorIs(t, err, usecase.ErrVideoLimits)
+		assert.Equal(t, entity.StatusUploading, video.Status)
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+		storage.AssertCalled(t, "DeleteObject", copyKey)
+	})
+}
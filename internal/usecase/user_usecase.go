@@ -2,23 +2,44 @@ package usecase
 
 import (
 	"errors"
+	"hackaton-service-api/internal/auth"
 	"hackaton-service-api/internal/entity"
 	"hackaton-service-api/internal/repository"
+	"time"
 )
 
 type TokenGenerator interface {
-	GenerateToken(userID string) (string, error)
+	GenerateToken(userID, sessionID string) (string, error)
 }
 
+// Em UserUseCase, Verification e Mailer são opcionais: sem eles o cadastro
+// não envia o e-mail de confirmação. VerifyURL é o endereço público de
+// GET /api/verify-email. ResendCooldown é o intervalo mínimo entre dois
+// e-mails de confirmação.
 type UserUseCase struct {
-	Repo  repository.UserRepository
-	Token TokenGenerator
+	Repo           repository.UserRepository
+	SessionRepo    repository.SessionRepository
+	Token          TokenGenerator
+	Verification   VerificationTokens
+	Mailer         Mailer
+	VerifyURL      string
+	ResendCooldown time.Duration
 }
 
-func NewUserUseCase(repo repository.UserRepository, token TokenGenerator) *UserUseCase {
+// AuthTokens é o par devolvido no login e a cada renovação de sessão.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+	Username     string
+}
+
+func NewUserUseCase(repo repository.UserRepository, sessionRepo repository.SessionRepository, token TokenGenerator) *UserUseCase {
 	return &UserUseCase{
-		Repo:  repo,
-		Token: token,
+		Repo:           repo,
+		SessionRepo:    sessionRepo,
+		Token:          token,
+		ResendCooldown: DefaultResendCooldown,
 	}
 }
 
@@ -38,23 +59,117 @@ func (uc *UserUseCase) Register(username, email, password string) error {
 		return err
 	}
 
-	return uc.Repo.Create(user)
+	if err := uc.Repo.Create(user); err != nil {
+		return err
+	}
+
+	// A conta já existe mesmo se o envio falhar; o link pode ser reenviado.
+	return uc.sendVerification(user)
 }
 
-func (uc *UserUseCase) Login(username, password string) (string, string, error) {
+func (uc *UserUseCase) Login(username, password, userAgent, ipAddress string) (*AuthTokens, error) {
 	user, err := uc.Repo.FindByUsername(username)
 	if err != nil {
-		return "", "", errors.New("credenciais inválidas")
+		return nil, errors.New("credenciais inválidas")
 	}
 
 	if !user.ValidatePassword(password) {
-		return "", "", errors.New("credenciais inválidas")
+		return nil, errors.New("credenciais inválidas")
+	}
+
+	session, refreshToken, err := entity.NewSession(user.ID, userAgent, ipAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.SessionRepo.Create(session); err != nil {
+		return nil, err
+	}
+
+	return uc.issueTokens(user, session, refreshToken)
+}
+
+// Refresh troca um refresh token válido por um novo par de tokens. Se um
+// token já rotacionado for reapresentado, a sessão inteira é revogada.
+func (uc *UserUseCase) Refresh(refreshToken string) (*AuthTokens, error) {
+	hash := entity.HashToken(refreshToken)
+
+	session, err := uc.SessionRepo.FindByTokenHash(hash)
+	if err != nil {
+		return nil, errors.New("sessão inválida")
+	}
+
+	if session.PreviousTokenHash == hash {
+		uc.SessionRepo.Revoke(session.ID, time.Now())
+		return nil, errors.New("sessão inválida")
+	}
+
+	if !session.IsActive() {
+		return nil, errors.New("sessão expirada")
+	}
+
+	user, err := uc.Repo.FindByID(session.UserID)
+	if err != nil {
+		return nil, errors.New("sessão inválida")
+	}
+
+	newToken, err := session.Rotate()
+	if err != nil {
+		return nil, err
+	}
+
+	// Outra renovação trocou o mesmo token entre a leitura e aqui: é tratada
+	// como reutilização, igual a um token já rotacionado.
+	rotated, err := uc.SessionRepo.Rotate(session, hash)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		uc.SessionRepo.Revoke(session.ID, time.Now())
+		return nil, errors.New("sessão inválida")
+	}
+
+	return uc.issueTokens(user, session, newToken)
+}
+
+func (uc *UserUseCase) Logout(userID, sessionID string) error {
+	session, err := uc.SessionRepo.FindByID(sessionID)
+	if err != nil || session.UserID != userID {
+		return errors.New("sessão não encontrada")
 	}
 
-	token, err := uc.Token.GenerateToken(user.ID)
+	// Só revoked_at é gravado: regravar a sessão lida desfaria uma renovação
+	// concorrente do refresh token.
+	return uc.SessionRepo.Revoke(session.ID, time.Now())
+}
+
+func (uc *UserUseCase) LogoutAll(userID string) error {
+	return uc.SessionRepo.RevokeAllByUserID(userID)
+}
+
+func (uc *UserUseCase) ListSessions(userID string) ([]entity.Session, error) {
+	return uc.SessionRepo.FindActiveByUserID(userID)
+}
+
+// IsSessionActive é consultado pelo middleware a cada requisição autenticada.
+func (uc *UserUseCase) IsSessionActive(sessionID string) bool {
+	session, err := uc.SessionRepo.FindByID(sessionID)
+	if err != nil {
+		return false
+	}
+	return session.IsActive()
+}
+
+func (uc *UserUseCase) issueTokens(user *entity.User, session *entity.Session, refreshToken string) (*AuthTokens, error) {
+	accessToken, err := uc.Token.GenerateToken(user.ID, session.ID)
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
-	return token, user.Username, nil
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(auth.AccessTokenTTL.Seconds()),
+		Username:     user.Username,
+	}, nil
 }
\ No newline at end of file
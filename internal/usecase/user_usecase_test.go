@@ -5,6 +5,7 @@ import (
 	"hackaton-service-api/internal/entity"
 	"hackaton-service-api/internal/usecase"
 	"testing"
+	"time"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -35,7 +36,7 @@ func TestUserUseCase_Register(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			repo := new(MockUserRepository)
 			tt.setup(repo)
-			uc := usecase.NewUserUseCase(repo, nil)
+			uc := usecase.NewUserUseCase(repo, nil, nil)
 			err := uc.Register(tt.username, tt.email, "123456")
 			if tt.wantErr != "" {
 				assert.EqualError(t, err, tt.wantErr)
@@ -51,49 +52,160 @@ func TestUserUseCase_Login(t *testing.T) {
 	
 	t.Run("Senha incorreta", func(t *testing.T) {
 		repo := new(MockUserRepository)
-		uc := usecase.NewUserUseCase(repo, nil)
+		uc := usecase.NewUserUseCase(repo, nil, nil)
 		repo.On("FindByUsername", "test").Return(user, nil)
-		_, _, err := uc.Login("test", "errada")
+		_, err := uc.Login("test", "errada", "", "")
 		assert.EqualError(t, err, "credenciais inválidas")
 	})
 
 	t.Run("Usuário inexistente", func(t *testing.T) {
         repo := new(MockUserRepository)
-        uc := usecase.NewUserUseCase(repo, nil)
+        uc := usecase.NewUserUseCase(repo, nil, nil)
 
         repo.On("FindByUsername", "fantasma").Return(nil, errors.New("not found"))
 
-        token, username, err := uc.Login("fantasma", "123")
+        tokens, err := uc.Login("fantasma", "123", "", "")
 
         assert.Error(t, err)
         assert.Equal(t, "credenciais inválidas", err.Error())
-        assert.Empty(t, token)
-        assert.Empty(t, username)
+        assert.Nil(t, tokens)
     })
 	
 	t.Run("Erro no token", func(t *testing.T) {
-		repo, tokenGen := new(MockUserRepository), new(MockTokenGenerator)
-		uc := usecase.NewUserUseCase(repo, tokenGen)
+		repo, sessions, tokenGen := new(MockUserRepository), new(MockSessionRepository), new(MockTokenGenerator)
+		uc := usecase.NewUserUseCase(repo, sessions, tokenGen)
 		repo.On("FindByUsername", "test").Return(user, nil)
-		tokenGen.On("GenerateToken", user.ID).Return("", errors.New("jwt error"))
-		_, _, err := uc.Login("test", "secret")
+		sessions.On("Create", mock.Anything).Return(nil)
+		tokenGen.On("GenerateToken", user.ID, mock.Anything).Return("", errors.New("jwt error"))
+		_, err := uc.Login("test", "secret", "", "")
 		assert.Error(t, err)
 	})
 
 	t.Run("Sucesso login", func(t *testing.T) {
-        repo := new(MockUserRepository)
-        tokenGen := new(MockTokenGenerator)
-        uc := usecase.NewUserUseCase(repo, tokenGen)
+        repo, sessions, tokenGen := new(MockUserRepository), new(MockSessionRepository), new(MockTokenGenerator)
+        uc := usecase.NewUserUseCase(repo, sessions, tokenGen)
 
         user, _ := entity.NewUser("test", "test@teste.com", "senha123")
         
         repo.On("FindByUsername", "test").Return(user, nil)
-        tokenGen.On("GenerateToken", user.ID).Return("token-valido", nil)
+        sessions.On("Create", mock.MatchedBy(func(s *entity.Session) bool {
+            return s.UserID == user.ID && s.UserAgent == "curl/8.0" && s.IPAddress == "10.0.0.1"
+        })).Return(nil)
+        tokenGen.On("GenerateToken", user.ID, mock.Anything).Return("token-valido", nil)
 
-        token, username, err := uc.Login("test", "senha123")
+        tokens, err := uc.Login("test", "senha123", "curl/8.0", "10.0.0.1")
 
         assert.NoError(t, err)
-        assert.Equal(t, "token-valido", token)
-        assert.Equal(t, "test", username)
+        assert.Equal(t, "token-valido", tokens.AccessToken)
+        assert.NotEmpty(t, tokens.RefreshToken)
+        assert.Equal(t, "test", tokens.Username)
     })
+}
+
+func TestUserUseCase_Refresh(t *testing.T) {
+	user, _ := entity.NewUser("test", "t@t.com", "secret")
+
+	t.Run("Token desconhecido", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(nil, sessions, nil)
+		sessions.On("FindByTokenHash", mock.Anything).Return(nil, errors.New("not found"))
+
+		_, err := uc.Refresh("qualquer")
+		assert.EqualError(t, err, "sessão inválida")
+	})
+
+	t.Run("Reuso de token rotacionado revoga a sessão", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(nil, sessions, nil)
+		session, oldToken, _ := entity.NewSession(user.ID, "", "")
+		session.Rotate()
+
+		sessions.On("FindByTokenHash", entity.HashToken(oldToken)).Return(session, nil)
+		sessions.On("Revoke", session.ID, mock.Anything).Return(nil)
+
+		_, err := uc.Refresh(oldToken)
+		assert.EqualError(t, err, "sessão inválida")
+		sessions.AssertCalled(t, "Revoke", session.ID, mock.Anything)
+	})
+
+	t.Run("Renovação simultânea com o mesmo token revoga a sessão", func(t *testing.T) {
+		repo, sessions := new(MockUserRepository), new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(repo, sessions, nil)
+		session, token, _ := entity.NewSession(user.ID, "", "")
+
+		sessions.On("FindByTokenHash", entity.HashToken(token)).Return(session, nil)
+		repo.On("FindByID", user.ID).Return(user, nil)
+		// A outra requisição rotacionou primeiro: nenhuma linha com o hash lido.
+		sessions.On("Rotate", session, entity.HashToken(token)).Return(false, nil)
+		sessions.On("Revoke", session.ID, mock.Anything).Return(nil)
+
+		_, err := uc.Refresh(token)
+		assert.EqualError(t, err, "sessão inválida")
+		sessions.AssertCalled(t, "Revoke", session.ID, mock.Anything)
+		sessions.AssertNotCalled(t, "Update", mock.Anything)
+	})
+
+	t.Run("Sessão revogada", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(nil, sessions, nil)
+		session, token, _ := entity.NewSession(user.ID, "", "")
+		session.Revoke()
+
+		sessions.On("FindByTokenHash", entity.HashToken(token)).Return(session, nil)
+
+		_, err := uc.Refresh(token)
+		assert.EqualError(t, err, "sessão expirada")
+	})
+
+	t.Run("Sucesso: Rotaciona o refresh token", func(t *testing.T) {
+		repo, sessions, tokenGen := new(MockUserRepository), new(MockSessionRepository), new(MockTokenGenerator)
+		uc := usecase.NewUserUseCase(repo, sessions, tokenGen)
+		session, token, _ := entity.NewSession(user.ID, "", "")
+
+		sessions.On("FindByTokenHash", entity.HashToken(token)).Return(session, nil)
+		repo.On("FindByID", user.ID).Return(user, nil)
+		sessions.On("Rotate", session, entity.HashToken(token)).Return(true, nil)
+		tokenGen.On("GenerateToken", user.ID, session.ID).Return("novo-access", nil)
+
+		tokens, err := uc.Refresh(token)
+		assert.NoError(t, err)
+		assert.Equal(t, "novo-access", tokens.AccessToken)
+		assert.NotEqual(t, token, tokens.RefreshToken)
+		assert.Equal(t, entity.HashToken(token), session.PreviousTokenHash)
+	})
+}
+
+func TestUserUseCase_Logout(t *testing.T) {
+	t.Run("Sessão de outro usuário", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(nil, sessions, nil)
+		sessions.On("FindByID", "s1").Return(&entity.Session{ID: "s1", UserID: "outro"}, nil)
+
+		err := uc.Logout("u1", "s1")
+		assert.EqualError(t, err, "sessão não encontrada")
+	})
+
+	t.Run("Sucesso: Sessão revogada deixa de ser ativa", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(nil, sessions, nil)
+		session := &entity.Session{ID: "s1", UserID: "u1", ExpiresAt: time.Now().Add(time.Hour)}
+		sessions.On("FindByID", "s1").Return(session, nil)
+		sessions.On("Revoke", "s1", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			at := args.Get(1).(time.Time)
+			session.RevokedAt = &at
+		})
+
+		assert.True(t, uc.IsSessionActive("s1"))
+		assert.NoError(t, uc.Logout("u1", "s1"))
+		assert.False(t, uc.IsSessionActive("s1"))
+	})
+
+	t.Run("Logout de todas as sessões", func(t *testing.T) {
+		sessions := new(MockSessionRepository)
+		uc := usecase.NewUserUseCase(nil, sessions, nil)
+		sessions.On("RevokeAllByUserID", "u1").Return(nil)
+
+		assert.NoError(t, uc.LogoutAll("u1"))
+		sessions.AssertCalled(t, "RevokeAllByUserID", "u1")
+	})
 }
\ No newline at end of file
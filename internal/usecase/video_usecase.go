@@ -1,22 +1,95 @@
 package usecase
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"hackaton-service-api/internal/entity"
 	"hackaton-service-api/internal/repository"
 	"fmt"
+	"io"
 	"mime/multipart"
-	"path/filepath"
+	"path"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrAccessDenied = errors.New("acesso negado")
+
+// ErrVideoProcessing impede remover um vídeo enquanto o worker ainda pode
+// gravar a saída dele.
+var ErrVideoProcessing = errors.New("vídeo em processamento não pode ser removido")
+
+var (
+	ErrVideoNotRetryable = errors.New("somente vídeos com erro podem ser reprocessados")
+	ErrInputUnavailable  = errors.New("arquivo original não está mais disponível")
+)
+
+// Vídeos removidos purgados por execução do PurgeDeleted.
+const purgeBatchSize = 100
+
+// ErrInvalidListQuery indica filtro, ordenação ou cursor inválido na listagem.
+var ErrInvalidListQuery = errors.New("parâmetros de listagem inválidos")
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
 )
 
+// ListVideosQuery são os parâmetros aceitos na listagem de vídeos. CreatedTo
+// é exclusivo; Limit zero usa DefaultPageSize.
+type ListVideosQuery struct {
+	Statuses    []string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	FileName    string
+	BatchID     string
+	Sort        string
+	Limit       int
+	Cursor      string
+}
+
+type VideoPage struct {
+	Items      []entity.Video `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Total      int64          `json:"-"`
+}
+
+// StatusUpdate é o que o worker informa ao mudar o estado de um vídeo.
+type StatusUpdate struct {
+	Actor        string
+	Status       entity.VideoStatus
+	OutputBucket string
+	OutputKey    string
+	OutputSize   int64
+	FrameCount   int
+	ErrorMessage string
+}
+
+// VideoDetail acrescenta ao vídeo as informações derivadas exibidas no detalhe.
+type VideoDetail struct {
+	entity.Video
+	ProcessingDurationSeconds *float64 `json:"processing_duration_seconds,omitempty"`
+	DownloadAvailable         bool     `json:"download_available"`
+}
+
 type FileStorageService interface {
 	UploadFile(file multipart.File, key string) error
-	GeneratePresignedURL(key string) (string, error)
+	// GeneratePresignedURL gera o link de download; fileName, quando
+	// informado, é o nome com que o navegador salva o arquivo.
+	GeneratePresignedURL(key, fileName string) (string, error)
+	DeleteObject(key string) error
+	ObjectExists(key string) (bool, error)
+	ObjectSize(key string) (int64, error)
+	ReadObject(key string) (io.ReadCloser, error)
+	AbortMultipartUpload(key, uploadID string) error
 	GetBucketName() string
 }
 
 type QueueService interface {
-	SendMessage(videoID, email string) error
+	SendMessage(message entity.VideoMessage) error
 }
 
 type VideoUseCase struct {
@@ -24,6 +97,10 @@ type VideoUseCase struct {
 	UserRepo repository.UserRepository
 	Storage FileStorageService
 	Queue   QueueService
+	Policy  UploadPolicy
+	Quota   *QuotaService
+	Requeue RequeuePolicy
+	Presets repository.PresetRepository
 }
 
 func NewVideoUseCase(repo repository.VideoRepository, userRepo repository.UserRepository, storage FileStorageService, queue QueueService) *VideoUseCase {
@@ -32,13 +109,35 @@ func NewVideoUseCase(repo repository.VideoRepository, userRepo repository.UserRe
         UserRepo: userRepo,
 		Storage:  storage,
 		Queue:    queue,
+		Policy:   DefaultUploadPolicy(),
+		Requeue:  DefaultRequeuePolicy(),
 	}
 }
 
-func (uc *VideoUseCase) RequestUpload(userID string, fileName string, file multipart.File) (*entity.Video, error) {
-	ext := filepath.Ext(fileName)
-	if ext != ".mp4" && ext != ".mkv" && ext != ".avi" {
-		return nil, fmt.Errorf("formato não suportado")
+// RequestUpload valida o vídeo pelo conteúdo (não pela extensão) e pelo
+// limite de tamanho do usuário antes de enviá-lo ao S3. As opções de
+// processamento, já resolvidas com o preset, ficam gravadas no vídeo e
+// seguem na mensagem para o worker.
+func (uc *VideoUseCase) RequestUpload(userID string, fileName string, file multipart.File, size int64, processing ProcessingRequest) (*entity.Video, error) {
+	options, presetID, err := uc.resolveProcessing(userID, processing)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.upload(userID, fileName, file, size, options, presetID, nil)
+}
+
+// upload recebe as opções já resolvidas, para que o lote as resolva uma vez
+// para todos os arquivos.
+func (uc *VideoUseCase) upload(userID, fileName string, file multipart.File, size int64, options entity.ProcessingOptions, presetID string, batchID *string) (*entity.Video, error) {
+	header, err := readHeader(file)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := uc.Policy.CheckContent(header)
+	if err != nil {
+		return nil, err
 	}
 
 	user, err := uc.UserRepo.FindByID(userID)
@@ -46,13 +145,30 @@ func (uc *VideoUseCase) RequestUpload(userID string, fileName string, file multi
 		return nil, fmt.Errorf("usuário não encontrado")
 	}
 
-	uniqueName := fmt.Sprintf("%d_%s", time.Now().Unix(), fileName)
+	if err := uc.Policy.CheckEmail(user); err != nil {
+		return nil, err
+	}
+
+	if err := uc.Policy.CheckSize(user, size); err != nil {
+		return nil, err
+	}
+
+	if err := uc.Quota.CheckUpload(user, size); err != nil {
+		return nil, err
+	}
+
+	uniqueName := uniqueFileName(fileName)
 	s3Key := "uploads/" + uniqueName
 
 	video := entity.NewVideo(userID, fileName, s3Key)
 	video.InputBucket = uc.Storage.GetBucketName()
+	video.InputSize = size
+	video.ContentType = format.ContentType()
+	video.Options = options
+	video.PresetID = presetID
+	video.BatchID = batchID
 
-	if err := uc.Repo.Create(video); err != nil {
+	if err := uc.Policy.inspect(video, file, size); err != nil {
 		return nil, err
 	}
 
@@ -60,33 +176,371 @@ func (uc *VideoUseCase) RequestUpload(userID string, fileName string, file multi
 		return nil, err
 	}
 
-	if err := uc.Queue.SendMessage(video.ID, user.Email); err != nil {
-		video.Status = entity.StatusError
-		video.ErrorMessage = "Falha ao enfileirar"
-		uc.Repo.Update(video)
+	// O vídeo só passa a existir junto com a mensagem para o worker, que o
+	// OutboxRelay publica na fila. Um arquivo sem registro é removido.
+	if err := uc.Repo.CreateWithOutbox(video, entity.NewVideoOutboxMessage(entity.NewVideoMessage(video, user.Email))); err != nil {
+		uc.Storage.DeleteObject(video.InputKey)
 		return nil, err
 	}
 
 	return video, nil
 }
 
-func (uc *VideoUseCase) ListByUser(userID string) ([]entity.Video, error) {
-	return uc.Repo.FindAllByUserID(userID)
+// uniqueFileName evita que arquivos de mesmo nome enviados no mesmo segundo
+// (comum em lotes) sobrescrevam um ao outro.
+func uniqueFileName(fileName string) string {
+	return fmt.Sprintf("%d_%s_%s", time.Now().Unix(), uuid.NewString()[:8], fileName)
+}
+
+// ListByUser devolve uma página dos vídeos do usuário. O cursor é opaco para
+// o cliente e só vale para a mesma ordenação em que foi gerado.
+func (uc *VideoUseCase) ListByUser(userID string, query ListVideosQuery) (*VideoPage, error) {
+	filter, err := query.toFilter(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	filter.Limit = limit + 1
+	videos, err := uc.Repo.FindPage(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	total, err := uc.Repo.Count(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &VideoPage{Items: videos, Total: total}
+	if len(videos) > limit {
+		page.Items = videos[:limit]
+		page.NextCursor = encodeVideoCursor(filter.Sort, page.Items[limit-1])
+	}
+	return page, nil
+}
+
+// GetDetail devolve o vídeo com os metadados de processamento, aplicando a
+// mesma checagem de dono do download.
+func (uc *VideoUseCase) GetDetail(userID, videoID string) (*VideoDetail, error) {
+	video, err := uc.findOwned(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := &VideoDetail{Video: *video, DownloadAvailable: video.DownloadAvailable()}
+	if duration, ok := video.ProcessingDuration(); ok {
+		seconds := duration.Seconds()
+		detail.ProcessingDurationSeconds = &seconds
+	}
+	return detail, nil
 }
 
 func (uc *VideoUseCase) GenerateDownloadURL(userID, videoID string) (string, error) {
-	video, err := uc.Repo.FindByID(videoID)
+	video, err := uc.findOwned(userID, videoID)
 	if err != nil {
 		return "", err
 	}
 
+	if !video.DownloadAvailable() {
+		return "", ErrVideoNotReady
+	}
+
+	return uc.Storage.GeneratePresignedURL(video.OutputKey, outputFileName(*video))
+}
+
+// GetForProcessing devolve o vídeo sem checagem de dono, para uso exclusivo
+// da API interna consumida pelo worker.
+func (uc *VideoUseCase) GetForProcessing(videoID string) (*entity.Video, error) {
+	return uc.Repo.FindByID(videoID)
+}
+
+func (uc *VideoUseCase) UpdateProcessingStatus(videoID string, update StatusUpdate) (*entity.Video, error) {
+	video, err := uc.Repo.FindByID(videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// PROCESSING sobre um vídeo já em PROCESSING é o heartbeat do worker, ou
+	// outro worker assumindo um vídeo reenviado à fila.
+	if video.Status == entity.StatusProcessing && update.Status == entity.StatusProcessing {
+		video.Heartbeat(time.Now())
+		if err := uc.Repo.Update(video); err != nil {
+			return nil, err
+		}
+		return video, nil
+	}
+
+	if !video.Status.WorkerCanTransitionTo(update.Status) {
+		return nil, fmt.Errorf("%w: %s -> %s", entity.ErrInvalidTransition, video.Status, update.Status)
+	}
+
+	reason := ""
+	switch update.Status {
+	case entity.StatusDone:
+		if update.OutputKey == "" {
+			return nil, fmt.Errorf("output_key é obrigatório para status DONE")
+		}
+		video.OutputBucket = update.OutputBucket
+		video.OutputKey = update.OutputKey
+		video.OutputSize = update.OutputSize
+		video.FrameCount = update.FrameCount
+		video.ErrorMessage = ""
+	case entity.StatusError:
+		if update.ErrorMessage == "" {
+			return nil, fmt.Errorf("error_message é obrigatório para status ERROR")
+		}
+		video.ErrorMessage = update.ErrorMessage
+		reason = update.ErrorMessage
+	}
+
+	if err := changeStatus(uc.Repo, video, update.Status, update.Actor, reason); err != nil {
+		return nil, err
+	}
+
+	return video, nil
+}
+
+// Retry devolve à fila um vídeo que terminou em ERROR reaproveitando o arquivo
+// já enviado, sem novo upload. Os reenvios automáticos recomeçam do zero.
+func (uc *VideoUseCase) Retry(userID, videoID string) (*entity.Video, error) {
+	video, err := uc.findOwned(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	if video.Status != entity.StatusError {
+		return nil, ErrVideoNotRetryable
+	}
+
+	if video.InputKey == "" {
+		return nil, ErrInputUnavailable
+	}
+	exists, err := uc.Storage.ObjectExists(video.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrInputUnavailable
+	}
+
+	user, err := uc.UserRepo.FindByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("usuário não encontrado")
+	}
+
+	if err := uc.Quota.CheckInFlight(user); err != nil {
+		return nil, err
+	}
+
+	event, err := video.TransitionTo(entity.StatusPending, entity.UserActor(userID), "Reprocessamento solicitado")
+	if err != nil {
+		return nil, err
+	}
+	video.ErrorMessage = ""
+	video.CorrelationID = uuid.New().String()
+	video.RetryAttempts++
+	video.RequeueAttempts = 0
+	video.NextRequeueAt = nil
+
+	if err := uc.Repo.UpdateStatusWithOutbox(video, event, entity.NewVideoOutboxMessage(entity.NewVideoMessage(video, user.Email))); err != nil {
+		return nil, err
+	}
+
+	return video, nil
+}
+
+func (uc *VideoUseCase) GetHistory(userID, videoID string) ([]entity.VideoStatusEvent, error) {
+	video, err := uc.findOwned(userID, videoID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.Repo.FindStatusHistory(video.ID)
+}
+
+// Delete cancela o vídeo se o worker ainda não o assumiu e o remove (soft
+// delete). Os arquivos no S3 só são apagados pelo PurgeDeleted, depois da
+// janela de retenção.
+func (uc *VideoUseCase) Delete(userID, videoID string) error {
+	video, err := uc.findOwned(userID, videoID)
+	if err != nil {
+		return err
+	}
+
+	if video.Status == entity.StatusProcessing {
+		return ErrVideoProcessing
+	}
+
+	if video.Status.CanTransitionTo(entity.StatusCanceled) {
+		if err := changeStatus(uc.Repo, video, entity.StatusCanceled, entity.UserActor(userID), "Removido pelo usuário"); err != nil {
+			return err
+		}
+	}
+
+	return uc.Repo.Delete(video)
+}
+
+// PurgeDeleted apaga os arquivos no S3 e remove de vez os vídeos excluídos há
+// mais que retention. Um vídeo cujo arquivo falhar ao apagar fica para a
+// próxima execução.
+func (uc *VideoUseCase) PurgeDeleted(retention time.Duration) (int, error) {
+	videos, err := uc.Repo.FindDeletedBefore(time.Now().Add(-retention), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for i := range videos {
+		if err := uc.purge(&videos[i]); err != nil {
+			errs = append(errs, fmt.Errorf("vídeo %s: %w", videos[i].ID, err))
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+func (uc *VideoUseCase) purge(video *entity.Video) error {
+	if video.UploadID != "" {
+		// O upload pode já ter sido abortado ou expirado; não impede o purge.
+		uc.Storage.AbortMultipartUpload(video.InputKey, video.UploadID)
+	}
+
+	for _, key := range []string{video.InputKey, video.OutputKey} {
+		if key == "" {
+			continue
+		}
+		if err := uc.Storage.DeleteObject(key); err != nil {
+			return err
+		}
+	}
+
+	return uc.Repo.Purge(video.ID)
+}
+
+// outputFileName é o nome do arquivo enviado, sem diretórios, com a extensão
+// da saída (o ZIP de frames).
+func outputFileName(video entity.Video) string {
+	base := path.Base(strings.ReplaceAll(video.FileName, `\`, "/"))
+	base = strings.TrimSuffix(base, path.Ext(base))
+	if base == "" || base == "." || base == "/" {
+		base = video.ID
+	}
+	return base + path.Ext(video.OutputKey)
+}
+
+func (uc *VideoUseCase) findOwned(userID, videoID string) (*entity.Video, error) {
+	video, err := uc.Repo.FindByID(videoID)
+	if err != nil {
+		return nil, err
+	}
+
 	if video.UserID != userID {
-		return "", fmt.Errorf("acesso negado")
+		return nil, ErrAccessDenied
+	}
+
+	return video, nil
+}
+
+// changeStatus aplica a transição no vídeo e persiste o novo estado junto com
+// o evento de histórico. Campos como ErrorMessage devem ser preenchidos antes.
+func changeStatus(repo repository.VideoRepository, video *entity.Video, to entity.VideoStatus, actor, reason string) error {
+	event, err := video.TransitionTo(to, actor, reason)
+	if err != nil {
+		return err
+	}
+	return repo.UpdateStatus(video, event)
+}
+
+func (q ListVideosQuery) toFilter(userID string) (repository.VideoFilter, error) {
+	filter := repository.VideoFilter{
+		UserID:      userID,
+		CreatedFrom: q.CreatedFrom,
+		CreatedTo:   q.CreatedTo,
+		FileName:    strings.TrimSpace(q.FileName),
+		BatchID:     strings.TrimSpace(q.BatchID),
+		Sort:        repository.VideoSort(q.Sort),
+		Limit:       q.Limit,
+	}
+
+	for _, s := range q.Statuses {
+		status := entity.VideoStatus(strings.ToUpper(strings.TrimSpace(s)))
+		if !status.Valid() {
+			return filter, fmt.Errorf("%w: status %q desconhecido", ErrInvalidListQuery, s)
+		}
+		filter.Statuses = append(filter.Statuses, status)
 	}
 
-	if video.Status != entity.StatusDone {
-		return "", fmt.Errorf("vídeo não está pronto")
+	if filter.BatchID != "" {
+		if _, err := uuid.Parse(filter.BatchID); err != nil {
+			return filter, fmt.Errorf("%w: batch_id inválido", ErrInvalidListQuery)
+		}
+	}
+
+	if !q.CreatedFrom.IsZero() && !q.CreatedTo.IsZero() && !q.CreatedFrom.Before(q.CreatedTo) {
+		return filter, fmt.Errorf("%w: created_from deve ser anterior a created_to", ErrInvalidListQuery)
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = repository.SortCreatedDesc
+	}
+	if !filter.Sort.Valid() {
+		return filter, fmt.Errorf("%w: ordenação %q não suportada", ErrInvalidListQuery, q.Sort)
+	}
+
+	switch {
+	case filter.Limit == 0:
+		filter.Limit = DefaultPageSize
+	case filter.Limit < 0:
+		return filter, fmt.Errorf("%w: limit deve ser positivo", ErrInvalidListQuery)
+	case filter.Limit > MaxPageSize:
+		filter.Limit = MaxPageSize
+	}
+
+	if q.Cursor != "" {
+		after, err := decodeVideoCursor(filter.Sort, q.Cursor)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = after
+	}
+
+	return filter, nil
+}
+
+// videoCursor é serializado em JSON e base64 (URL-safe) para o cliente.
+type videoCursor struct {
+	Sort  repository.VideoSort `json:"s"`
+	Value time.Time            `json:"v"`
+	ID    string               `json:"id"`
+}
+
+func encodeVideoCursor(sort repository.VideoSort, last entity.Video) string {
+	cursor := videoCursor{Sort: sort, Value: last.CreatedAt, ID: last.ID}
+	if column, _ := sort.Column(); column == "updated_at" {
+		cursor.Value = last.UpdatedAt
+	}
+
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeVideoCursor(sort repository.VideoSort, encoded string) (*repository.VideoCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", ErrInvalidListQuery)
+	}
+
+	var cursor videoCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || uuid.Validate(cursor.ID) != nil {
+		return nil, fmt.Errorf("%w: cursor inválido", ErrInvalidListQuery)
+	}
+	if cursor.Sort != sort {
+		return nil, fmt.Errorf("%w: cursor gerado para outra ordenação", ErrInvalidListQuery)
 	}
 
-	return uc.Storage.GeneratePresignedURL(video.OutputKey)
+	return &repository.VideoCursor{SortValue: cursor.Value, ID: cursor.ID}, nil
 }
\ No newline at end of file
@@ -3,8 +3,14 @@ package usecase_test
 import (
 	"errors"
 	"hackaton-service-api/internal/entity"
+	"hackaton-service-api/internal/infra/storage"
+	"hackaton-service-api/internal/media"
+	"hackaton-service-api/internal/repository"
 	"hackaton-service-api/internal/usecase"
+	"strings"
 	"testing"
+	"time"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -12,90 +18,233 @@ import (
 func TestVideoUseCase_RequestUpload(t *testing.T) {
 	t.Run("Erro: Formato de arquivo não suportado", func(t *testing.T) {
 		uc := usecase.NewVideoUseCase(nil, nil, nil, nil)
-		video, err := uc.RequestUpload("user1", "documento.pdf", nil)
+		video, err := uc.RequestUpload("user1", "documento.pdf", videoFile([]byte("%PDF-1.7")), 1024, usecase.ProcessingRequest{})
 
 		assert.Nil(t, video)
 		assert.EqualError(t, err, "formato não suportado")
 	})
 
+	t.Run("Erro: PDF renomeado para .mp4", func(t *testing.T) {
+		uc := usecase.NewVideoUseCase(nil, nil, nil, nil)
+		_, err := uc.RequestUpload("user1", "video.mp4", videoFile([]byte("%PDF-1.7\n%âãÏÓ")), 1024, usecase.ProcessingRequest{})
+
+		assert.ErrorIs(t, err, usecase.ErrUnsupportedFormat)
+	})
+
+	t.Run("Erro: Formato desabilitado na política", func(t *testing.T) {
+		uc := usecase.NewVideoUseCase(nil, nil, nil, nil)
+		uc.Policy.AllowedFormats = []media.Format{media.FormatMKV}
+
+		_, err := uc.RequestUpload("user1", "video.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
+		assert.ErrorIs(t, err, usecase.ErrUnsupportedFormat)
+	})
+
+	t.Run("Erro: Arquivo acima do limite do usuário", func(t *testing.T) {
+		userRepo := new(MockUserRepository)
+		uc := usecase.NewVideoUseCase(nil, userRepo, nil, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, MaxUploadSize: 1 << 20}, nil)
+
+		_, err := uc.RequestUpload("u1", "video.mp4", videoFile(mp4Header), 2<<20, usecase.ProcessingRequest{})
+		assert.ErrorIs(t, err, usecase.ErrFileTooLarge)
+	})
+
+	t.Run("Erro: Cota do plano esgotada não grava no S3", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
+		plans := new(MockPlanRepository)
+		uc.Quota = usecase.NewQuotaService(plans, userRepo, repo)
+
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, ID: "u1", PlanID: "free"}, nil)
+		plans.On("FindByID", "free").Return(&entity.Plan{ID: "free", MaxStorageBytes: 1 << 20}, nil)
+		repo.On("Usage", "u1", mock.Anything).Return(&repository.VideoUsage{StoredBytes: 1 << 20}, nil)
+
+		_, err := uc.RequestUpload("u1", "video.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
+		assert.ErrorIs(t, err, usecase.ErrQuotaExceeded)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+		storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Erro: Vídeo acima da resolução máxima", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
+		uc.Policy.MaxWidth, uc.Policy.MaxHeight = 1920, 1080
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt}, nil)
+		storage.On("GetBucketName").Return("bucket")
+
+		_, err := uc.RequestUpload("u1", "video.mp4", videoFile(sampleMP4(60, 3840, 2160)), 1024, usecase.ProcessingRequest{})
+		assert.ErrorIs(t, err, usecase.ErrVideoLimits)
+		repo.AssertNotCalled(t, "Create", mock.Anything)
+		storage.AssertNotCalled(t, "UploadFile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Vídeo vertical dentro do limite grava os metadados", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
+		uc.Policy.MaxWidth, uc.Policy.MaxHeight = 1920, 1080
+		uc.Policy.MaxDuration = 10 * time.Minute
+		content := sampleMP4(90, 1080, 1920)
+
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("GetBucketName").Return("bucket")
+		storage.On("UploadFile", mock.Anything, mock.Anything).Return(nil)
+		repo.On("CreateWithOutbox", mock.Anything, mock.Anything).Return(nil)
+
+		video, err := uc.RequestUpload("u1", "video.mp4", videoFile(content), int64(len(content)), usecase.ProcessingRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, 90.0, video.DurationSeconds)
+		assert.Equal(t, 1080, video.Width)
+		assert.Equal(t, 1920, video.Height)
+		assert.Equal(t, "h264", video.VideoCodec)
+		assert.Equal(t, int64(len(content)*8/90), video.Bitrate)
+	})
+
 	t.Run("Erro: Usuário não encontrado", func(t *testing.T) {
 		userRepo := new(MockUserRepository)
 		uc := usecase.NewVideoUseCase(nil, userRepo, nil, nil)
 
 		userRepo.On("FindByID", "user_fantasma").Return(nil, errors.New("not found"))
 
-		video, err := uc.RequestUpload("user_fantasma", "video.mp4", nil)
+		video, err := uc.RequestUpload("user_fantasma", "video.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
 		assert.Nil(t, video)
 		assert.Contains(t, err.Error(), "usuário não encontrado")
 	})
 
-	t.Run("Erro: Falha ao criar registro no banco", func(t *testing.T) {
+	t.Run("Erro: Falha ao criar registro no banco remove o arquivo enviado", func(t *testing.T) {
 		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
 		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
 
-		userRepo.On("FindByID", "u1").Return(&entity.User{Email: "u@u.com"}, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "u@u.com"}, nil)
 		storage.On("GetBucketName").Return("bucket")
-		repo.On("Create", mock.Anything).Return(errors.New("db error"))
+		storage.On("UploadFile", mock.Anything, mock.Anything).Return(nil)
+		repo.On("CreateWithOutbox", mock.Anything, mock.Anything).Return(errors.New("db error"))
+		storage.On("DeleteObject", mock.MatchedBy(func(key string) bool { return strings.HasPrefix(key, "uploads/") })).Return(nil)
 
-		video, err := uc.RequestUpload("u1", "v.mp4", nil)
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
 		assert.Nil(t, video)
 		assert.EqualError(t, err, "db error")
+		storage.AssertCalled(t, "DeleteObject", mock.Anything)
 	})
 
 	t.Run("Erro: Falha no upload para o Storage", func(t *testing.T) {
 		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
 		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
 
-		userRepo.On("FindByID", "u1").Return(&entity.User{Email: "u@u.com"}, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "u@u.com"}, nil)
 		storage.On("GetBucketName").Return("bucket")
-		repo.On("Create", mock.Anything).Return(nil)
 		storage.On("UploadFile", mock.Anything, mock.Anything).Return(errors.New("s3 error"))
 
-		video, err := uc.RequestUpload("u1", "v.mp4", nil)
+		video, err := uc.RequestUpload("u1", "v.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{})
 		assert.Nil(t, video)
 		assert.EqualError(t, err, "s3 error")
+		repo.AssertNotCalled(t, "CreateWithOutbox", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Erro na fila SQS", func(t *testing.T) {
-		repo, userRepo, storage, queue := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService), new(MockQueueService)
-		uc := usecase.NewVideoUseCase(repo, userRepo, storage, queue)
+	t.Run("Sucesso: Fluxo completo de upload", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
 
-		userRepo.On("FindByID", "u1").Return(&entity.User{Email: "e@e.com"}, nil)
-		storage.On("GetBucketName").Return("b")
-		repo.On("Create", mock.Anything).Return(nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		storage.On("GetBucketName").Return("bucket")
 		storage.On("UploadFile", mock.Anything, mock.Anything).Return(nil)
-		queue.On("SendMessage", mock.Anything, "e@e.com").Return(errors.New("sqs fail"))
-		repo.On("Update", mock.Anything).Return(nil) // Cobre o handleError do UseCase
+		repo.On("CreateWithOutbox", mock.Anything, mock.MatchedBy(func(msg *entity.OutboxMessage) bool {
+			message, err := msg.VideoMessage()
+			return err == nil && message.VideoID == msg.VideoID && message.Email == "e@e.com" &&
+				message.SchemaVersion == entity.VideoMessageSchemaVersion && message.InputBucket == "bucket" &&
+				message.Options == entity.ProcessingOptions{FrameInterval: 0.5, OutputFormat: "jpeg", MaxFrames: 0}
+		})).Return(nil)
+
+		options := entity.ProcessingOptions{FrameInterval: 0.5, OutputFormat: "JPG"}
+		video, err := uc.RequestUpload("u1", "video.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{Options: options})
+		assert.NoError(t, err)
+		assert.NotNil(t, video)
+		assert.Equal(t, "video.mp4", video.FileName)
+		assert.Equal(t, "video/mp4", video.ContentType)
+		assert.Equal(t, "jpeg", video.Options.OutputFormat)
+		assert.NotEmpty(t, video.CorrelationID)
+	})
 
-		_, err := uc.RequestUpload("u1", "v.mp4", nil)
-		assert.Error(t, err)
+	t.Run("Erro: Opções de processamento inválidas", func(t *testing.T) {
+		uc := usecase.NewVideoUseCase(nil, nil, nil, nil)
+
+		_, err := uc.RequestUpload("u1", "video.mp4", videoFile(mp4Header), 1024, usecase.ProcessingRequest{Options: entity.ProcessingOptions{OutputFormat: "gif"}})
+		assert.ErrorIs(t, err, usecase.ErrInvalidOptions)
 	})
 
-	t.Run("Sucesso: Fluxo completo de upload", func(t *testing.T) {
-		repo, userRepo, storage, queue := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService), new(MockQueueService)
-		uc := usecase.NewVideoUseCase(repo, userRepo, storage, queue)
+	t.Run("Sucesso: Arquivo gravado no armazenamento em memória", func(t *testing.T) {
+		repo, userRepo := new(MockVideoRepository), new(MockUserRepository)
+		store := storage.NewService(storage.NewMemoryBlobs(), "local", storage.URLSigner{Secret: []byte("s"), TTL: time.Minute})
+		uc := usecase.NewVideoUseCase(repo, userRepo, store, nil)
 
-		userRepo.On("FindByID", "u1").Return(&entity.User{Email: "e@e.com"}, nil)
-		storage.On("GetBucketName").Return("bucket")
-		repo.On("Create", mock.Anything).Return(nil)
-		storage.On("UploadFile", mock.Anything, mock.Anything).Return(nil)
-		queue.On("SendMessage", mock.Anything, "e@e.com").Return(nil) // Agora retorna nil para sucesso
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, Email: "e@e.com"}, nil)
+		repo.On("CreateWithOutbox", mock.Anything, mock.Anything).Return(nil)
 
-		video, err := uc.RequestUpload("u1", "video.mp4", nil)
+		video, err := uc.RequestUpload("u1", "video.mp4", videoFile(mp4Header), int64(len(mp4Header)), usecase.ProcessingRequest{})
 		assert.NoError(t, err)
-		assert.NotNil(t, video)
-		assert.Equal(t, "video.mp4", video.FileName)
+		assert.Equal(t, "local", video.InputBucket)
+
+		header, err := store.ReadObjectHeader(video.InputKey, int64(len(mp4Header)))
+		assert.NoError(t, err)
+		assert.Equal(t, mp4Header, header)
 	})
 }
 
 func TestVideoUseCase_ListByUser(t *testing.T) {
-	repo := new(MockVideoRepository)
-	uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
-	repo.On("FindAllByUserID", "u1").Return([]entity.Video{{ID: "1"}}, nil)
+	t.Run("Sucesso: Padrões aplicados e sem próxima página", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
 
-	videos, err := uc.ListByUser("u1")
-	assert.NoError(t, err)
-	assert.Len(t, videos, 1)
+		expected := repository.VideoFilter{UserID: "u1", Sort: repository.SortCreatedDesc, Limit: usecase.DefaultPageSize + 1}
+		repo.On("FindPage", expected).Return([]entity.Video{{ID: "1"}}, nil)
+		repo.On("Count", expected).Return(int64(1), nil)
+
+		page, err := uc.ListByUser("u1", usecase.ListVideosQuery{})
+		assert.NoError(t, err)
+		assert.Len(t, page.Items, 1)
+		assert.Empty(t, page.NextCursor)
+		assert.Equal(t, int64(1), page.Total)
+	})
+
+	t.Run("Sucesso: Cursor da próxima página aponta para o último item", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+
+		t1 := time.Date(2026, 1, 2, 10, 0, 0, 0, time.UTC)
+		t2 := t1.Add(-time.Hour)
+		lastID := uuid.NewString()
+		repo.On("FindPage", mock.MatchedBy(func(f repository.VideoFilter) bool { return f.After == nil })).
+			Return([]entity.Video{{ID: uuid.NewString(), CreatedAt: t1}, {ID: lastID, CreatedAt: t2}, {ID: uuid.NewString()}}, nil).Once()
+		repo.On("Count", mock.Anything).Return(int64(5), nil)
+
+		page, err := uc.ListByUser("u1", usecase.ListVideosQuery{Limit: 2})
+		assert.NoError(t, err)
+		assert.Len(t, page.Items, 2)
+		assert.NotEmpty(t, page.NextCursor)
+
+		repo.On("FindPage", mock.MatchedBy(func(f repository.VideoFilter) bool {
+			return f.After != nil && f.After.ID == lastID && f.After.SortValue.Equal(t2)
+		})).Return([]entity.Video{}, nil).Once()
+
+		_, err = uc.ListByUser("u1", usecase.ListVideosQuery{Limit: 2, Cursor: page.NextCursor})
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Erro: Parâmetros inválidos", func(t *testing.T) {
+		uc := usecase.NewVideoUseCase(new(MockVideoRepository), nil, nil, nil)
+		now := time.Now()
+
+		queries := map[string]usecase.ListVideosQuery{
+			"status":    {Statuses: []string{"FOO"}},
+			"sort":      {Sort: "file_name"},
+			"limit":     {Limit: -1},
+			"cursor":    {Cursor: "não-é-base64"},
+			"intervalo": {CreatedFrom: now, CreatedTo: now.Add(-time.Hour)},
+		}
+		for name, query := range queries {
+			_, err := uc.ListByUser("u1", query)
+			assert.ErrorIs(t, err, usecase.ErrInvalidListQuery, name)
+		}
+	})
 }
 
 func TestVideoUseCase_GenerateDownloadURL(t *testing.T) {
@@ -141,14 +290,274 @@ func TestVideoUseCase_GenerateDownloadURL(t *testing.T) {
 
 		video := &entity.Video{
 			UserID:    "u1",
+			FileName:  "aula.mp4",
 			Status:    entity.StatusDone,
 			OutputKey: "final.zip",
 		}
 		repo.On("FindByID", "v1").Return(video, nil)
-		storage.On("GeneratePresignedURL", "final.zip").Return("http://aws-link.com/file", nil)
+		storage.On("GeneratePresignedURL", "final.zip", "aula.zip").Return("http://aws-link.com/file", nil)
 
 		url, err := uc.GenerateDownloadURL("u1", "v1")
 		assert.NoError(t, err)
 		assert.Equal(t, "http://aws-link.com/file", url)
 	})
-}
\ No newline at end of file
+}
+
+func TestVideoUseCase_UpdateProcessingStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    entity.VideoStatus
+		update  usecase.StatusUpdate
+		wantErr error
+	}{
+		{"PENDING -> PROCESSING", entity.StatusPending, usecase.StatusUpdate{Status: entity.StatusProcessing}, nil},
+		{"PROCESSING -> DONE", entity.StatusProcessing, usecase.StatusUpdate{Status: entity.StatusDone, OutputBucket: "b", OutputKey: "out.zip"}, nil},
+		{"PROCESSING -> ERROR", entity.StatusProcessing, usecase.StatusUpdate{Status: entity.StatusError, ErrorMessage: "ffmpeg falhou"}, nil},
+		{"DONE -> PROCESSING é rejeitado", entity.StatusDone, usecase.StatusUpdate{Status: entity.StatusProcessing}, entity.ErrInvalidTransition},
+		{"PENDING -> DONE é rejeitado", entity.StatusPending, usecase.StatusUpdate{Status: entity.StatusDone, OutputKey: "out.zip"}, entity.ErrInvalidTransition},
+		{"ERROR -> PROCESSING é rejeitado", entity.StatusError, usecase.StatusUpdate{Status: entity.StatusProcessing}, entity.ErrInvalidTransition},
+		// Transições válidas na máquina de estados, mas que não são do worker.
+		{"UPLOADING -> PENDING é rejeitado", entity.StatusUploading, usecase.StatusUpdate{Status: entity.StatusPending}, entity.ErrInvalidTransition},
+		{"PENDING -> CANCELED é rejeitado", entity.StatusPending, usecase.StatusUpdate{Status: entity.StatusCanceled}, entity.ErrInvalidTransition},
+		{"PROCESSING -> PENDING é rejeitado", entity.StatusProcessing, usecase.StatusUpdate{Status: entity.StatusPending}, entity.ErrInvalidTransition},
+		{"ERROR -> PENDING é rejeitado", entity.StatusError, usecase.StatusUpdate{Status: entity.StatusPending}, entity.ErrInvalidTransition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockVideoRepository)
+			uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+			repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", Status: tt.from}, nil)
+			repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+			video, err := uc.UpdateProcessingStatus("v1", tt.update)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.update.Status, video.Status)
+		})
+	}
+
+	t.Run("DONE sem output_key", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", Status: entity.StatusProcessing}, nil)
+
+		_, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusDone})
+		assert.Error(t, err)
+	})
+
+	t.Run("Vídeo inexistente", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v404").Return(nil, repository.ErrNotFound)
+
+		_, err := uc.UpdateProcessingStatus("v404", usecase.StatusUpdate{Status: entity.StatusProcessing})
+		assert.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func TestVideoUseCase_UpdateProcessingStatus_RecordsHistory(t *testing.T) {
+	repo := new(MockVideoRepository)
+	uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+	repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", Status: entity.StatusProcessing, Version: 3}, nil)
+	repo.On("UpdateStatus", mock.Anything, mock.MatchedBy(func(e *entity.VideoStatusEvent) bool {
+		return e.FromStatus == entity.StatusProcessing && e.ToStatus == entity.StatusError &&
+			e.Actor == "service:worker" && e.Reason == "ffmpeg falhou"
+	})).Return(nil)
+
+	_, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{
+		Actor:        entity.ServiceActor("worker"),
+		Status:       entity.StatusError,
+		ErrorMessage: "ffmpeg falhou",
+	})
+	assert.NoError(t, err)
+}
+
+func TestVideoUseCase_UpdateProcessingStatus_ConcurrentUpdate(t *testing.T) {
+	repo := new(MockVideoRepository)
+	uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+	repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", Status: entity.StatusPending}, nil)
+	repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(repository.ErrConcurrentUpdate)
+
+	_, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusProcessing})
+	assert.ErrorIs(t, err, repository.ErrConcurrentUpdate)
+}
+
+func TestVideoUseCase_GetHistory(t *testing.T) {
+	t.Run("Erro: Acesso negado", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "dono"}, nil)
+
+		_, err := uc.GetHistory("hacker", "v1")
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+
+	t.Run("Sucesso", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1"}, nil)
+		repo.On("FindStatusHistory", "v1").Return([]entity.VideoStatusEvent{{ToStatus: entity.StatusProcessing}}, nil)
+
+		events, err := uc.GetHistory("u1", "v1")
+		assert.NoError(t, err)
+		assert.Len(t, events, 1)
+	})
+}
+
+
+func TestVideoUseCase_GetDetail(t *testing.T) {
+	t.Run("Erro: Acesso negado", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "dono"}, nil)
+
+		_, err := uc.GetDetail("hacker", "v1")
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+
+	t.Run("Sucesso: Metadados do processamento", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		video := &entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusPending}
+		repo.On("FindByID", "v1").Return(video, nil)
+		repo.On("UpdateStatus", mock.Anything, mock.Anything).Return(nil)
+
+		_, err := uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusProcessing})
+		assert.NoError(t, err)
+		_, err = uc.UpdateProcessingStatus("v1", usecase.StatusUpdate{Status: entity.StatusDone, OutputKey: "out.zip", OutputSize: 2048, FrameCount: 120})
+		assert.NoError(t, err)
+
+		detail, err := uc.GetDetail("u1", "v1")
+		assert.NoError(t, err)
+		assert.True(t, detail.DownloadAvailable)
+		assert.Equal(t, int64(2048), detail.OutputSize)
+		assert.Equal(t, 120, detail.FrameCount)
+		assert.NotNil(t, detail.ProcessingDurationSeconds)
+	})
+
+	t.Run("Sucesso: Sem download antes de concluir", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusProcessing}, nil)
+
+		detail, err := uc.GetDetail("u1", "v1")
+		assert.NoError(t, err)
+		assert.False(t, detail.DownloadAvailable)
+		assert.Nil(t, detail.ProcessingDurationSeconds)
+	})
+}
+
+func TestVideoUseCase_Delete(t *testing.T) {
+	t.Run("Erro: Vídeo em processamento", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusProcessing}, nil)
+
+		err := uc.Delete("u1", "v1")
+		assert.ErrorIs(t, err, usecase.ErrVideoProcessing)
+		repo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Sucesso: PENDING é cancelado antes de remover", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		video := &entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusPending}
+		repo.On("FindByID", "v1").Return(video, nil)
+		repo.On("UpdateStatus", video, mock.Anything).Return(nil)
+		repo.On("Delete", video).Return(nil)
+
+		assert.NoError(t, uc.Delete("u1", "v1"))
+		assert.Equal(t, entity.StatusCanceled, video.Status)
+	})
+
+	t.Run("Sucesso: DONE é removido sem mudar status", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		video := &entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusDone}
+		repo.On("FindByID", "v1").Return(video, nil)
+		repo.On("Delete", video).Return(nil)
+
+		assert.NoError(t, uc.Delete("u1", "v1"))
+		repo.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything)
+	})
+}
+
+func TestVideoUseCase_Retry(t *testing.T) {
+	failed := func() *entity.Video {
+		return &entity.Video{ID: "v1", UserID: "u1", InputKey: "uploads/1_v.mp4", Status: entity.StatusError, ErrorMessage: "Falha ao enfileirar", RequeueAttempts: 3}
+	}
+
+	t.Run("Erro: Vídeo que não está em ERROR", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(&entity.Video{ID: "v1", UserID: "u1", Status: entity.StatusDone}, nil)
+
+		_, err := uc.Retry("u1", "v1")
+		assert.ErrorIs(t, err, usecase.ErrVideoNotRetryable)
+	})
+
+	t.Run("Erro: Vídeo de outro usuário", func(t *testing.T) {
+		repo := new(MockVideoRepository)
+		uc := usecase.NewVideoUseCase(repo, nil, nil, nil)
+		repo.On("FindByID", "v1").Return(failed(), nil)
+
+		_, err := uc.Retry("u2", "v1")
+		assert.ErrorIs(t, err, usecase.ErrAccessDenied)
+	})
+
+	t.Run("Erro: Arquivo original não existe mais", func(t *testing.T) {
+		repo, storage := new(MockVideoRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, nil, storage, nil)
+		repo.On("FindByID", "v1").Return(failed(), nil)
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(false, nil)
+
+		_, err := uc.Retry("u1", "v1")
+		assert.ErrorIs(t, err, usecase.ErrInputUnavailable)
+		repo.AssertNotCalled(t, "UpdateStatusWithOutbox", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Sucesso: Volta para PENDING e é enfileirado", func(t *testing.T) {
+		repo, userRepo, storage := new(MockVideoRepository), new(MockUserRepository), new(MockStorageService)
+		uc := usecase.NewVideoUseCase(repo, userRepo, storage, nil)
+		repo.On("FindByID", "v1").Return(failed(), nil)
+		storage.On("ObjectExists", "uploads/1_v.mp4").Return(true, nil)
+		userRepo.On("FindByID", "u1").Return(&entity.User{EmailVerifiedAt: &verifiedAt, ID: "u1", Email: "e@e.com"}, nil)
+		repo.On("UpdateStatusWithOutbox", mock.Anything, mock.MatchedBy(func(e *entity.VideoStatusEvent) bool {
+			return e.FromStatus == entity.StatusError && e.ToStatus == entity.StatusPending && e.Actor == entity.UserActor("u1")
+		}), mock.MatchedBy(func(msg *entity.OutboxMessage) bool {
+			message, err := msg.VideoMessage()
+			return err == nil && message.VideoID == "v1" && message.Email == "e@e.com"
+		})).Return(nil)
+
+		video, err := uc.Retry("u1", "v1")
+		assert.NoError(t, err)
+		assert.Equal(t, entity.StatusPending, video.Status)
+		assert.Empty(t, video.ErrorMessage)
+		assert.Equal(t, 1, video.RetryAttempts)
+		assert.Zero(t, video.RequeueAttempts)
+	})
+}
+
+func TestVideoUseCase_PurgeDeleted(t *testing.T) {
+	repo, storage := new(MockVideoRepository), new(MockStorageService)
+	uc := usecase.NewVideoUseCase(repo, nil, storage, nil)
+
+	repo.On("FindDeletedBefore", mock.Anything, mock.Anything).Return([]entity.Video{
+		{ID: "v1", InputKey: "uploads/a.mp4", OutputKey: "outputs/a.zip"},
+		{ID: "v2", InputKey: "uploads/b.mp4"},
+	}, nil)
+	storage.On("DeleteObject", "uploads/a.mp4").Return(nil)
+	storage.On("DeleteObject", "outputs/a.zip").Return(nil)
+	storage.On("DeleteObject", "uploads/b.mp4").Return(errors.New("s3 indisponível"))
+	repo.On("Purge", "v1").Return(nil)
+
+	purged, err := uc.PurgeDeleted(24 * time.Hour)
+	assert.Equal(t, 1, purged)
+	assert.Error(t, err)
+	repo.AssertNotCalled(t, "Purge", "v2")
+}